@@ -0,0 +1,126 @@
+// Package backofftest provides utilities for testing code which uses backoff.
+package backofftest
+
+import (
+	"sync"
+	"time"
+
+	"github.com/austinov/go-recipes/backoff"
+)
+
+// FakeClock is a backoff.Clock which time is moved only by Advance.
+// It is safe for concurrent use.
+type FakeClock struct {
+	// this mutex protects now and timers
+	mu     sync.Mutex
+	cond   *sync.Cond
+	now    time.Time
+	timers []*fakeTimer
+}
+
+type fakeTimer struct {
+	clock  *FakeClock
+	when   time.Time
+	c      chan time.Time
+	active bool
+}
+
+// NewFakeClock returns a FakeClock set to the passed time.
+func NewFakeClock(now time.Time) *FakeClock {
+	c := &FakeClock{now: now}
+	c.cond = sync.NewCond(&c.mu)
+	return c
+}
+
+// Now returns the current fake time.
+func (c *FakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+// After returns a channel which receives the fake time
+// when the clock is advanced by d.
+func (c *FakeClock) After(d time.Duration) <-chan time.Time {
+	return c.NewTimer(d).C()
+}
+
+// NewTimer returns a timer which fires when the clock is advanced by d.
+func (c *FakeClock) NewTimer(d time.Duration) backoff.Timer {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	t := &fakeTimer{
+		clock:  c,
+		when:   c.now.Add(d),
+		c:      make(chan time.Time, 1),
+		active: true,
+	}
+	if d <= 0 {
+		t.fire(c.now)
+	} else {
+		c.timers = append(c.timers, t)
+	}
+	c.cond.Broadcast()
+	return t
+}
+
+// Advance moves the clock forward and fires the expired timers.
+func (c *FakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+	timers := c.timers[:0]
+	for _, t := range c.timers {
+		if !t.when.After(c.now) {
+			t.fire(c.now)
+		} else {
+			timers = append(timers, t)
+		}
+	}
+	c.timers = timers
+}
+
+// Timers returns number of timers waiting to fire.
+func (c *FakeClock) Timers() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.timers)
+}
+
+// BlockUntil blocks until at least n timers are waiting to fire.
+// It helps to synchronize with a goroutine waiting on the clock.
+func (c *FakeClock) BlockUntil(n int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for len(c.timers) < n {
+		c.cond.Wait()
+	}
+}
+
+// fire sends the time to the channel of the timer.
+// It must be called under the lock of the clock.
+func (t *fakeTimer) fire(now time.Time) {
+	t.active = false
+	t.c <- now
+}
+
+func (t *fakeTimer) C() <-chan time.Time {
+	return t.c
+}
+
+func (t *fakeTimer) Stop() bool {
+	c := t.clock
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !t.active {
+		return false
+	}
+	t.active = false
+	for i, ct := range c.timers {
+		if ct == t {
+			c.timers = append(c.timers[:i], c.timers[i+1:]...)
+			break
+		}
+	}
+	return true
+}
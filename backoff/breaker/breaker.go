@@ -0,0 +1,289 @@
+/*
+Package breaker implements the circuit breaker pattern
+to stop calling a failing dependency for a while.
+
+Usage:
+
+	b := breaker.New(breaker.DefaultConfig)
+	err := b.Execute(func() error {
+		return callSomething()
+	})
+	if err == breaker.ErrOpen {
+		// the dependency is considered unavailable
+	}
+*/
+package breaker
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/austinov/go-recipes/backoff"
+)
+
+// State is a state of the circuit breaker.
+type State int
+
+const (
+	// Closed lets all requests to pass.
+	Closed State = iota
+	// Open rejects all requests until the open interval is elapsed.
+	Open
+	// HalfOpen lets a limited number of trial requests to pass.
+	HalfOpen
+)
+
+func (s State) String() string {
+	switch s {
+	case Closed:
+		return "closed"
+	case Open:
+		return "open"
+	case HalfOpen:
+		return "half-open"
+	}
+	return "unknown"
+}
+
+var (
+	// ErrOpen is returned when the breaker is open.
+	ErrOpen = errors.New("breaker: circuit is open")
+	// ErrTooManyRequests is returned when the breaker is half-open
+	// and the trial requests are already in flight.
+	ErrTooManyRequests = errors.New("breaker: too many requests")
+)
+
+// Counts holds the numbers of requests and their results
+// since the last change of the state or the last interval.
+type Counts struct {
+	Requests             uint64
+	Successes            uint64
+	Failures             uint64
+	ConsecutiveSuccesses uint64
+	ConsecutiveFailures  uint64
+}
+
+// TripPolicy decides whether the closed breaker should trip to open.
+type TripPolicy func(c Counts) bool
+
+// ConsecutiveFailures trips the breaker after n failures in a row.
+func ConsecutiveFailures(n uint64) TripPolicy {
+	return func(c Counts) bool {
+		return c.ConsecutiveFailures >= n
+	}
+}
+
+// FailureRatio trips the breaker when the ratio of failures reaches ratio
+// and at least minRequests requests were made.
+func FailureRatio(ratio float64, minRequests uint64) TripPolicy {
+	return func(c Counts) bool {
+		return c.Requests >= minRequests && float64(c.Failures) >= ratio*float64(c.Requests)
+	}
+}
+
+// Config defines the config for Breaker.
+type Config struct {
+	// Trip is ConsecutiveFailures(5) if omitted.
+	Trip TripPolicy
+	// Interval is the period to clear counts in the closed state,
+	// counts are cleared only on state changes if omitted.
+	Interval time.Duration
+	// Backoff defines the open interval which grows exponentially
+	// while trial requests fail. The clock of the breaker is Backoff.Clock.
+	Backoff backoff.Config
+	// HalfOpenRequests is the number of successful trial requests
+	// to close the breaker, 1 if omitted.
+	HalfOpenRequests uint64
+	// OnStateChange is called on every change of the state.
+	// It is called under the lock of the breaker so it must not call the breaker.
+	OnStateChange func(from, to State)
+}
+
+// DefaultConfig is the default Breaker config.
+var DefaultConfig = Config{
+	Trip: ConsecutiveFailures(5),
+	Backoff: backoff.Config{
+		MinDelay: 1 * time.Second,
+		MaxDelay: 1 * time.Minute,
+		Expo:     2.0,
+		Jitter:   0.1,
+	},
+	HalfOpenRequests: 1,
+}
+
+// Breaker is a thread-safe circuit breaker.
+type Breaker struct {
+	config  Config
+	clock   backoff.Clock
+	backoff *backoff.ExpBackoff
+
+	// this mutex protects all fields below
+	mu         sync.Mutex
+	state      State
+	generation uint64
+	counts     Counts
+	expiry     time.Time
+	inFlight   uint64
+}
+
+// New returns a Breaker by passed configuration.
+func New(config Config) *Breaker {
+	if config.Trip == nil {
+		config.Trip = DefaultConfig.Trip
+	}
+	if config.Backoff.MinDelay == 0 {
+		config.Backoff.MinDelay = DefaultConfig.Backoff.MinDelay
+	}
+	if config.HalfOpenRequests == 0 {
+		config.HalfOpenRequests = DefaultConfig.HalfOpenRequests
+	}
+	clock := config.Backoff.Clock
+	if clock == nil {
+		clock = backoff.SystemClock
+	}
+	b := &Breaker{
+		config:  config,
+		clock:   clock,
+		backoff: backoff.NewExpBackoffWithConfig(config.Backoff),
+	}
+	b.newGeneration(clock.Now())
+	return b
+}
+
+// State returns the current state of the breaker.
+func (b *Breaker) State() State {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.updateState(b.clock.Now())
+	return b.state
+}
+
+// Counts returns the counts of the current state.
+func (b *Breaker) Counts() Counts {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.updateState(b.clock.Now())
+	return b.counts
+}
+
+// Allow checks whether a request may proceed.
+// If so it returns the function which must be called with the result of the request,
+// otherwise it returns ErrOpen or ErrTooManyRequests.
+func (b *Breaker) Allow() (func(err error), error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.updateState(b.clock.Now())
+	switch b.state {
+	case Open:
+		return nil, ErrOpen
+	case HalfOpen:
+		if b.inFlight >= b.config.HalfOpenRequests-b.counts.ConsecutiveSuccesses {
+			return nil, ErrTooManyRequests
+		}
+		b.inFlight++
+	}
+	b.counts.Requests++
+	generation := b.generation
+	return func(err error) {
+		b.done(generation, err)
+	}, nil
+}
+
+// Execute calls op if the breaker allows it and records the result.
+func (b *Breaker) Execute(op func() error) error {
+	done, err := b.Allow()
+	if err != nil {
+		return err
+	}
+	err = op()
+	done(err)
+	return err
+}
+
+func (b *Breaker) done(generation uint64, err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	now := b.clock.Now()
+	b.updateState(now)
+	if generation != b.generation {
+		// the result belongs to the previous state
+		return
+	}
+	if b.state == HalfOpen {
+		b.inFlight--
+	}
+	if err == nil {
+		b.onSuccess(now)
+	} else {
+		b.onFailure(now)
+	}
+}
+
+func (b *Breaker) onSuccess(now time.Time) {
+	b.counts.Successes++
+	b.counts.ConsecutiveSuccesses++
+	b.counts.ConsecutiveFailures = 0
+	if b.state == HalfOpen && b.counts.ConsecutiveSuccesses >= b.config.HalfOpenRequests {
+		b.backoff.Reset()
+		b.setState(Closed, now)
+	}
+}
+
+func (b *Breaker) onFailure(now time.Time) {
+	b.counts.Failures++
+	b.counts.ConsecutiveFailures++
+	b.counts.ConsecutiveSuccesses = 0
+	switch b.state {
+	case Closed:
+		if b.config.Trip(b.counts) {
+			b.setState(Open, now)
+		}
+	case HalfOpen:
+		b.setState(Open, now)
+	}
+}
+
+// updateState switches the open breaker to half-open when the open interval
+// is elapsed and clears the counts of the closed breaker on the interval.
+func (b *Breaker) updateState(now time.Time) {
+	if b.expiry.IsZero() || now.Before(b.expiry) {
+		return
+	}
+	switch b.state {
+	case Closed:
+		b.newGeneration(now)
+	case Open:
+		b.setState(HalfOpen, now)
+	}
+}
+
+func (b *Breaker) setState(state State, now time.Time) {
+	if b.state == state {
+		return
+	}
+	prev := b.state
+	b.state = state
+	b.newGeneration(now)
+	if b.config.OnStateChange != nil {
+		b.config.OnStateChange(prev, state)
+	}
+}
+
+func (b *Breaker) newGeneration(now time.Time) {
+	b.generation++
+	b.counts = Counts{}
+	b.inFlight = 0
+	switch b.state {
+	case Closed:
+		if b.config.Interval > 0 {
+			b.expiry = now.Add(b.config.Interval)
+		} else {
+			b.expiry = time.Time{}
+		}
+	case Open:
+		b.expiry = now.Add(b.backoff.NextDelay())
+	default:
+		b.expiry = time.Time{}
+	}
+}
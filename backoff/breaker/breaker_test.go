@@ -0,0 +1,145 @@
+package breaker
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/austinov/go-recipes/backoff"
+	"github.com/austinov/go-recipes/backoff/backofftest"
+	"github.com/stretchr/testify/assert"
+)
+
+var errFailure = errors.New("failure")
+
+func fail() error {
+	return errFailure
+}
+
+func succeed() error {
+	return nil
+}
+
+func TestConsecutiveFailures(t *testing.T) {
+	var changes []string
+	clock := backofftest.NewFakeClock(time.Unix(0, 0))
+	b := New(Config{
+		Trip: ConsecutiveFailures(3),
+		Backoff: backoff.Config{
+			MinDelay:   time.Second,
+			MaxDelay:   4 * time.Second,
+			JitterMode: backoff.JitterNone,
+			Clock:      clock,
+		},
+		OnStateChange: func(from, to State) {
+			changes = append(changes, from.String()+"->"+to.String())
+		},
+	})
+
+	assert.Equal(t, errFailure, b.Execute(fail))
+	assert.Equal(t, errFailure, b.Execute(fail))
+	assert.NoError(t, b.Execute(succeed))
+	assert.Equal(t, errFailure, b.Execute(fail))
+	assert.Equal(t, errFailure, b.Execute(fail))
+	assert.Equal(t, Closed, b.State())
+	assert.Equal(t, errFailure, b.Execute(fail))
+	assert.Equal(t, Open, b.State())
+	assert.Equal(t, ErrOpen, b.Execute(succeed))
+
+	// the open interval grows exponentially while trial requests fail
+	for _, interval := range []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 4 * time.Second} {
+		clock.Advance(interval - 1)
+		assert.Equal(t, Open, b.State(), "open interval %v", interval)
+		clock.Advance(1)
+		assert.Equal(t, HalfOpen, b.State(), "open interval %v", interval)
+		assert.Equal(t, errFailure, b.Execute(fail))
+		assert.Equal(t, Open, b.State())
+	}
+
+	clock.Advance(4 * time.Second)
+	assert.NoError(t, b.Execute(succeed))
+	assert.Equal(t, Closed, b.State())
+
+	// the open interval is reset after closing
+	assert.Equal(t, errFailure, b.Execute(fail))
+	assert.Equal(t, errFailure, b.Execute(fail))
+	assert.Equal(t, errFailure, b.Execute(fail))
+	clock.Advance(time.Second)
+	assert.Equal(t, HalfOpen, b.State())
+
+	assert.Equal(t, []string{
+		"closed->open",
+		"open->half-open", "half-open->open",
+		"open->half-open", "half-open->open",
+		"open->half-open", "half-open->open",
+		"open->half-open", "half-open->open",
+		"open->half-open", "half-open->closed",
+		"closed->open",
+		"open->half-open",
+	}, changes)
+}
+
+func TestFailureRatio(t *testing.T) {
+	b := New(Config{Trip: FailureRatio(0.5, 4)})
+
+	assert.Equal(t, errFailure, b.Execute(fail))
+	assert.Equal(t, errFailure, b.Execute(fail))
+	assert.Equal(t, errFailure, b.Execute(fail))
+	assert.Equal(t, Closed, b.State(), "not enough requests")
+	assert.NoError(t, b.Execute(succeed))
+	assert.NoError(t, b.Execute(succeed))
+	assert.NoError(t, b.Execute(succeed))
+	assert.Equal(t, Closed, b.State())
+	assert.Equal(t, Counts{Requests: 6, Successes: 3, Failures: 3, ConsecutiveSuccesses: 3}, b.Counts())
+	assert.Equal(t, errFailure, b.Execute(fail))
+	assert.Equal(t, Open, b.State())
+}
+
+func TestHalfOpenRequests(t *testing.T) {
+	clock := backofftest.NewFakeClock(time.Unix(0, 0))
+	b := New(Config{
+		Trip: ConsecutiveFailures(1),
+		Backoff: backoff.Config{
+			MinDelay:   time.Second,
+			MaxDelay:   4 * time.Second,
+			JitterMode: backoff.JitterNone,
+			Clock:      clock,
+		},
+	})
+
+	assert.Equal(t, errFailure, b.Execute(fail))
+	clock.Advance(time.Second)
+
+	done, err := b.Allow()
+	assert.NoError(t, err)
+	_, err = b.Allow()
+	assert.Equal(t, ErrTooManyRequests, err)
+	done(nil)
+	assert.Equal(t, Closed, b.State())
+}
+
+func TestStaleResult(t *testing.T) {
+	b := New(Config{Trip: ConsecutiveFailures(1)})
+
+	done, err := b.Allow()
+	assert.NoError(t, err)
+	assert.Equal(t, errFailure, b.Execute(fail))
+	assert.Equal(t, Open, b.State())
+	// the result of the request started in the closed state is ignored
+	done(nil)
+	assert.Equal(t, Open, b.State())
+}
+
+func TestInterval(t *testing.T) {
+	clock := backofftest.NewFakeClock(time.Unix(0, 0))
+	b := New(Config{
+		Trip:     ConsecutiveFailures(2),
+		Interval: time.Minute,
+		Backoff:  backoff.Config{Clock: clock},
+	})
+	assert.Equal(t, errFailure, b.Execute(fail))
+	clock.Advance(time.Minute)
+	assert.Equal(t, Counts{}, b.Counts())
+	assert.Equal(t, errFailure, b.Execute(fail))
+	assert.Equal(t, Closed, b.State())
+}
@@ -0,0 +1,91 @@
+package backoff
+
+import (
+	"errors"
+	"sync"
+)
+
+// ErrBudgetExhausted is returned when the retry budget denies a retry.
+var ErrBudgetExhausted = errors.New("retry budget exhausted")
+
+// Budget is a thread-safe token bucket shared by many retrying processes.
+// Every successful call deposits Ratio tokens and every retry withdraws one token,
+// so in the long run retries are limited to Ratio of successful calls.
+// The bucket initially holds MaxTokens to allow retries before any success.
+//
+// A nil *Budget allows all retries.
+type Budget struct {
+	ratio     float64
+	maxTokens float64
+
+	// this mutex protects tokens and counters
+	mu        sync.Mutex
+	tokens    float64
+	successes uint64
+	retries   uint64
+	denied    uint64
+}
+
+// BudgetStats holds the counters of Budget.
+type BudgetStats struct {
+	Successes uint64
+	Retries   uint64
+	Denied    uint64
+	Tokens    float64
+}
+
+// NewBudget returns a Budget which allows ratio retries per successful call
+// and holds up to maxTokens retries.
+func NewBudget(ratio, maxTokens float64) *Budget {
+	return &Budget{
+		ratio:     ratio,
+		maxTokens: maxTokens,
+		tokens:    maxTokens,
+	}
+}
+
+// Deposit records a successful call.
+func (b *Budget) Deposit() {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.successes++
+	b.tokens += b.ratio
+	if b.tokens > b.maxTokens {
+		b.tokens = b.maxTokens
+	}
+}
+
+// Withdraw returns true if a retry is allowed and records it,
+// otherwise it records the retry as denied.
+func (b *Budget) Withdraw() bool {
+	if b == nil {
+		return true
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.tokens < 1 {
+		b.denied++
+		return false
+	}
+	b.tokens--
+	b.retries++
+	return true
+}
+
+// Stats returns the counters of the budget.
+func (b *Budget) Stats() BudgetStats {
+	if b == nil {
+		return BudgetStats{}
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return BudgetStats{
+		Successes: b.successes,
+		Retries:   b.retries,
+		Denied:    b.denied,
+		Tokens:    b.tokens,
+	}
+}
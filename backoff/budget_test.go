@@ -0,0 +1,80 @@
+package backoff
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBudget(t *testing.T) {
+	b := NewBudget(0.5, 2)
+	assert.True(t, b.Withdraw())
+	assert.True(t, b.Withdraw())
+	assert.False(t, b.Withdraw())
+	b.Deposit()
+	assert.False(t, b.Withdraw())
+	b.Deposit()
+	assert.True(t, b.Withdraw())
+	for i := 0; i < 10; i++ {
+		b.Deposit()
+	}
+	assert.Equal(t, BudgetStats{Successes: 12, Retries: 3, Denied: 2, Tokens: 2}, b.Stats())
+
+	var nilBudget *Budget
+	nilBudget.Deposit()
+	assert.True(t, nilBudget.Withdraw())
+	assert.Equal(t, BudgetStats{}, nilBudget.Stats())
+}
+
+func TestSharedBudget(t *testing.T) {
+	budget := NewBudget(0.1, 3)
+	config := Config{
+		MinDelay: time.Millisecond,
+		MaxDelay: time.Millisecond,
+		Budget:   budget,
+	}
+	fail := func() error {
+		return errFailure
+	}
+	err1 := Retry(context.Background(), fail, WithBackoff(New(config)), WithMaxAttempts(3))
+	err2 := Retry(context.Background(), fail, WithBackoff(New(config)), WithMaxAttempts(3))
+
+	var re *RetryError
+	if assert.True(t, errors.As(err1, &re)) {
+		assert.Equal(t, ErrMaxAttempts, re.Reason)
+		assert.Equal(t, uint64(3), re.Attempts)
+	}
+	if assert.True(t, errors.As(err2, &re)) {
+		assert.Equal(t, ErrBudgetExhausted, re.Reason)
+		assert.Equal(t, uint64(2), re.Attempts)
+	}
+	assert.Equal(t, BudgetStats{Retries: 3, Denied: 1}, budget.Stats())
+
+	assert.NoError(t, Retry(context.Background(), func() error {
+		return nil
+	}, WithBackoff(New(config))))
+	assert.Equal(t, uint64(1), budget.Stats().Successes)
+}
+
+func TestBudgetDelay(t *testing.T) {
+	budget := NewBudget(0, 1)
+	eb := NewExpBackoffWithConfig(Config{
+		MinDelay: time.Millisecond,
+		MaxDelay: time.Millisecond,
+		Budget:   budget,
+	})
+	_, ok := <-eb.Delay()
+	assert.True(t, ok)
+	_, ok = <-eb.Delay()
+	assert.False(t, ok)
+	timer := eb.Timer()
+	_, ok = <-timer.C()
+	assert.False(t, ok)
+	assert.False(t, timer.Stop())
+	assert.Equal(t, ErrBudgetExhausted, eb.Wait(context.Background()))
+	assert.Equal(t, uint64(1), eb.Attempts())
+	assert.Equal(t, BudgetStats{Retries: 1, Denied: 3}, budget.Stats())
+}
@@ -0,0 +1,73 @@
+package backoff
+
+import (
+	"math/rand"
+	"time"
+)
+
+// Clock provides the time functions used by strategies and Retry.
+// It allows to replace the real time in tests.
+type Clock interface {
+	Now() time.Time
+	After(d time.Duration) <-chan time.Time
+	NewTimer(d time.Duration) Timer
+}
+
+// Timer is a stoppable timer created by Clock.
+type Timer interface {
+	// C returns the channel on which the time is delivered.
+	C() <-chan time.Time
+	// Stop prevents the timer from firing.
+	Stop() bool
+}
+
+// SystemClock is the Clock based on the time package.
+var SystemClock Clock = systemClock{}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+func (systemClock) After(d time.Duration) <-chan time.Time {
+	return time.After(d)
+}
+
+func (systemClock) NewTimer(d time.Duration) Timer {
+	return systemTimer{time.NewTimer(d)}
+}
+
+type systemTimer struct {
+	*time.Timer
+}
+
+func (t systemTimer) C() <-chan time.Time {
+	return t.Timer.C
+}
+
+// configured is implemented by the strategies of this package
+// to share their configuration with Wait and Retry.
+type configured interface {
+	settings() Config
+}
+
+// settingsOf returns the configuration of the strategy
+// or the default one for the strategies implemented outside.
+func settingsOf(s Strategy) Config {
+	if c, ok := s.(configured); ok {
+		return c.settings()
+	}
+	return Config{Clock: SystemClock}
+}
+
+// withDefaults fills the clock and the random source of config.
+func withDefaults(config Config) Config {
+	if config.Clock == nil {
+		config.Clock = SystemClock
+	}
+	if config.Rand == nil {
+		config.Rand = rand.NewSource(time.Now().UnixNano())
+	}
+	return config
+}
@@ -1,41 +1,68 @@
 package backoff
 
 import (
+	"context"
 	"math/rand"
 	"sync"
 	"time"
 )
 
-// Config defines the config for ExpBackoff
+// Config defines the config for ExpBackoff and other strategies
 type Config struct {
+	Policy   Policy
 	MinDelay time.Duration
 	MaxDelay time.Duration
 	Expo     float64
-	Jitter   float64
+	// Jitter is the standard deviation of JitterNormal relative to the delay
+	Jitter     float64
+	JitterMode JitterMode
+	// Step is the delay increment of PolicyLinear
+	Step time.Duration
+	// Clock is SystemClock if omitted
+	Clock Clock
+	// Rand is the source of jitter, a new one seeded by current time if omitted.
+	// The source is not safe for concurrent use so it must not be shared between strategies.
+	Rand rand.Source
+	// Budget limits retries of all strategies sharing it, no limit if omitted.
+	// Retry and Wait return ErrBudgetExhausted when it denies a retry,
+	// the channels of Delay and Timer are closed without a value.
+	Budget *Budget
+	// OnRetry is called before every retry with the number of unsuccessful attempts,
+	// the chosen delay and the last error if it is known.
+	OnRetry func(attempt uint64, delay time.Duration, err error)
+	// Metrics receives measurements of retries and waiting
+	Metrics Metrics
+	// HintMode defines how a delay passed to Hint is applied
+	HintMode HintMode
+	// Decay is the idle interval after which ExpBackoff divides the current delay by Expo,
+	// no decay if omitted
+	Decay time.Duration
 }
 
-// ExpBackoff is a thread-safe implemention of
+// ExpBackoff is a thread-safe Strategy implemention of
 // the backoff algorithm to exponential increase
 // the delay between repeated processes
 // in the case of unsuccessful attempts.
 type ExpBackoff struct {
 	config Config
 
-	// this mutex protects delay and attempts
-	mu       sync.Mutex
-	delay    time.Duration
-	attempts uint64
+	// this mutex protects delay, attempts, lastAttempt, rnd and hint
+	mu          sync.Mutex
+	delay       time.Duration
+	attempts    uint64
+	lastAttempt time.Time
+	rnd         *rand.Rand
+	hint        hint
 }
 
 var (
 	// DefaultConfig is the default ExpBackoff config.
 	DefaultConfig = Config{
-		100 * time.Millisecond,
-		1 * time.Minute,
-		2.0,
-		0.1,
+		MinDelay: 100 * time.Millisecond,
+		MaxDelay: 1 * time.Minute,
+		Expo:     2.0,
+		Jitter:   0.1,
 	}
-	rnd = rand.New(rand.NewSource(time.Now().UnixNano()))
 )
 
 // NewExpBackoff returns an ExpBackoff with default configuration.
@@ -51,32 +78,68 @@ func NewExpBackoffWithConfig(config Config) *ExpBackoff {
 	if config.Expo == 0 {
 		config.Expo = DefaultConfig.Expo
 	}
+	config = withDefaults(config)
 	return &ExpBackoff{
 		config: config,
 		delay:  config.MinDelay,
+		rnd:    rand.New(config.Rand),
 	}
 }
 
-// Delay waits for exponentially increased duration and send the current time
-// on the returned channel.
-func (e *ExpBackoff) Delay() <-chan time.Time {
+// NextDelay returns exponentially increased delay.
+func (e *ExpBackoff) NextDelay() time.Duration {
+	delay, _ := e.nextDelay()
+	return delay
+}
+
+func (e *ExpBackoff) nextDelay() (time.Duration, uint64) {
 	e.mu.Lock()
+	defer e.mu.Unlock()
+	now := e.config.Clock.Now()
+	e.decay(now)
 	currDelay := e.delay
-	nextDelay := currDelay
-	e.mu.Unlock()
-
-	nextDelay *= time.Duration(e.config.Expo)
+	nextDelay := time.Duration(float64(currDelay) * e.config.Expo)
 	if nextDelay > e.config.MaxDelay {
 		nextDelay = e.config.MaxDelay
 	}
-	normal := time.Duration(rnd.NormFloat64() * e.config.Jitter * float64(time.Millisecond))
-	nextDelay += normal
-
-	e.mu.Lock()
 	e.delay = nextDelay
 	e.attempts++
+	delay := e.hint.take(e.config, jitter(e.config, e.rnd, currDelay))
+	// the next attempt is made after the delay, the backoff is not idle while waiting
+	e.lastAttempt = now.Add(delay)
+	return delay, e.attempts
+}
+
+// Hint sets the delay of the next attempt according to HintMode,
+// the exponential increase of delays is not affected.
+func (e *ExpBackoff) Hint(d time.Duration) {
+	e.mu.Lock()
+	e.hint = hint{delay: d, set: true}
 	e.mu.Unlock()
-	return time.After(currDelay)
+}
+
+// Delay waits for exponentially increased duration and send the current time
+// on the returned channel.
+// The underlying timer can not be stopped until it fires,
+// so Wait or Timer should be preferred if the caller may give up waiting.
+// If the retry budget denies the retry, the channel is closed without a value.
+func (e *ExpBackoff) Delay() <-chan time.Time {
+	if !e.config.Budget.Withdraw() {
+		return denied
+	}
+	return newTimer(e.config, nextDelay(e, e.config, nil)).C()
+}
+
+// Wait waits for exponentially increased duration
+// or returns the context error if the context is done earlier.
+// It returns ErrBudgetExhausted without waiting if the retry budget denies the retry.
+func (e *ExpBackoff) Wait(ctx context.Context) error {
+	return Wait(ctx, e)
+}
+
+// Timer returns a stoppable timer which fires after exponentially increased duration.
+func (e *ExpBackoff) Timer() Timer {
+	return NewTimer(e)
 }
 
 // Reset sets current delay the minimum value
@@ -84,6 +147,7 @@ func (e *ExpBackoff) Reset() {
 	e.mu.Lock()
 	e.attempts = 0
 	e.delay = e.config.MinDelay
+	e.lastAttempt = time.Time{}
 	e.mu.Unlock()
 }
 
@@ -93,3 +157,7 @@ func (e *ExpBackoff) Attempts() uint64 {
 	defer e.mu.Unlock()
 	return e.attempts
 }
+
+func (e *ExpBackoff) settings() Config {
+	return e.config
+}
@@ -1,21 +1,27 @@
-package backoff
+package backoff_test
 
 import (
+	"context"
+	"errors"
 	"fmt"
-	"math"
+	"math/rand"
 	"testing"
 	"time"
 
+	"github.com/austinov/go-recipes/backoff"
+	"github.com/austinov/go-recipes/backoff/backofftest"
 	"github.com/stretchr/testify/assert"
 )
 
 func TestDelay(t *testing.T) {
 	assert := assert.New(t)
-	eb := NewExpBackoffWithConfig(Config{
-		MinDelay: 100 * time.Millisecond,
-		MaxDelay: 5 * time.Second,
-		Expo:     2.0,
-		Jitter:   0.1,
+	clock := backofftest.NewFakeClock(time.Unix(0, 0))
+	eb := backoff.NewExpBackoffWithConfig(backoff.Config{
+		MinDelay:   100 * time.Millisecond,
+		MaxDelay:   5 * time.Second,
+		Expo:       2.0,
+		JitterMode: backoff.JitterNone,
+		Clock:      clock,
 	})
 	assert.NotNil(eb)
 
@@ -70,14 +76,63 @@ func TestDelay(t *testing.T) {
 		if c.reset {
 			eb.Reset()
 		}
-		t1 := time.Now()
-		t2 := <-eb.Delay()
-		delta := math.Ceil(float64(c.expected) * 0.01)
-		t.Logf("T%d %v, %v, %v", i+1, c.expected, t2.Sub(t1), c.expected-t2.Sub(t1))
-		assert.InDelta(
-			float64(c.expected),
-			float64(t2.Sub(t1)),
-			delta,
-			fmt.Sprintf("delay %d attempt ~%v", i+1, c.expected))
+		t1 := clock.Now()
+		ch := eb.Delay()
+		clock.Advance(c.expected - 1)
+		select {
+		case <-ch:
+			t.Errorf("delay %d attempt fired before %v", i+1, c.expected)
+		default:
+		}
+		clock.Advance(1)
+		select {
+		case t2 := <-ch:
+			assert.Equal(c.expected, t2.Sub(t1), fmt.Sprintf("delay %d attempt %v", i+1, c.expected))
+		default:
+			t.Errorf("delay %d attempt not fired after %v", i+1, c.expected)
+		}
+	}
+}
+
+func TestRandSource(t *testing.T) {
+	config := backoff.Config{
+		MinDelay:   time.Second,
+		MaxDelay:   time.Minute,
+		JitterMode: backoff.JitterFull,
+	}
+	config.Rand = rand.NewSource(42)
+	eb1 := backoff.NewExpBackoffWithConfig(config)
+	config.Rand = rand.NewSource(42)
+	eb2 := backoff.NewExpBackoffWithConfig(config)
+	for i := 0; i < 10; i++ {
+		assert.Equal(t, eb1.NextDelay(), eb2.NextDelay(), "attempt %d", i+1)
+	}
+}
+
+func TestRetryMaxElapsedTime(t *testing.T) {
+	clock := backofftest.NewFakeClock(time.Unix(0, 0))
+	eb := backoff.NewExpBackoffWithConfig(backoff.Config{
+		MinDelay:   time.Second,
+		MaxDelay:   time.Minute,
+		JitterMode: backoff.JitterNone,
+		Clock:      clock,
+	})
+	done := make(chan error)
+	go func() {
+		done <- backoff.Retry(context.Background(), func() error {
+			return errors.New("failure")
+		}, backoff.WithBackoff(eb), backoff.WithMaxElapsedTime(5*time.Second))
+	}()
+	// attempts at 0s, 1s, 3s and 7s, the last one exhausts the time budget
+	for _, d := range []time.Duration{time.Second, 2 * time.Second, 4 * time.Second} {
+		clock.BlockUntil(1)
+		clock.Advance(d)
+	}
+	err := <-done
+	var re *backoff.RetryError
+	if assert.True(t, errors.As(err, &re)) {
+		assert.Equal(t, backoff.ErrMaxElapsedTime, re.Reason)
+		assert.Equal(t, uint64(4), re.Attempts)
 	}
+	assert.Equal(t, time.Unix(7, 0), clock.Now())
 }
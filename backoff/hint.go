@@ -0,0 +1,116 @@
+package backoff
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// HintMode defines how a delay hint is applied.
+type HintMode int
+
+const (
+	// HintClamp clamps the hint to MinDelay and MaxDelay.
+	HintClamp HintMode = iota
+	// HintOverride uses the hint as is.
+	HintOverride
+)
+
+var hintNames = []string{
+	HintClamp:    "clamp",
+	HintOverride: "override",
+}
+
+// String returns the name of the hint mode.
+func (m HintMode) String() string {
+	if m < 0 || int(m) >= len(hintNames) {
+		return "unknown"
+	}
+	return hintNames[m]
+}
+
+// Set parses the hint mode name, so a HintMode can be used as a flag.Value.
+func (m *HintMode) Set(s string) error {
+	for i, name := range hintNames {
+		if strings.EqualFold(s, name) {
+			*m = HintMode(i)
+			return nil
+		}
+	}
+	return errors.New("unknown hint mode " + s)
+}
+
+// Hinter is implemented by the strategies which accept
+// an externally dictated delay, e.g. from Retry-After header.
+type Hinter interface {
+	// Hint sets the delay of the next attempt instead of the computed one.
+	Hint(d time.Duration)
+}
+
+// RetryAfterError is a failure with the delay dictated by the failed side.
+type RetryAfterError struct {
+	Err   error
+	Delay time.Duration
+}
+
+func (e *RetryAfterError) Error() string {
+	return fmt.Sprintf("%v (retry after %v)", e.Err, e.Delay)
+}
+
+// Unwrap returns the wrapped error.
+func (e *RetryAfterError) Unwrap() error {
+	return e.Err
+}
+
+// RetryAfter wraps err so that Retry waits for the delay d before the next attempt
+// (according to the HintMode of the strategy).
+func RetryAfter(err error, d time.Duration) error {
+	if err == nil {
+		return nil
+	}
+	return &RetryAfterError{Err: err, Delay: d}
+}
+
+// HintError passes the delay of RetryAfterError wrapped in err to the strategy.
+// It returns false if err has no delay or the strategy does not accept hints.
+func HintError(s Strategy, err error) bool {
+	var ra *RetryAfterError
+	if !errors.As(err, &ra) {
+		return false
+	}
+	h, ok := s.(Hinter)
+	if ok {
+		h.Hint(ra.Delay)
+	}
+	return ok
+}
+
+// hint is a delay dictated from outside for the next attempt.
+type hint struct {
+	delay time.Duration
+	set   bool
+}
+
+// take returns the hinted delay according to the HintMode of config
+// or the computed delay if there is no hint, and clears the hint.
+// It must be called under the lock of the strategy.
+func (h *hint) take(config Config, delay time.Duration) time.Duration {
+	if !h.set {
+		return delay
+	}
+	delay = h.delay
+	*h = hint{}
+	if delay < 0 {
+		delay = 0
+	}
+	if config.HintMode == HintClamp {
+		if delay < config.MinDelay {
+			delay = config.MinDelay
+		}
+		if config.MaxDelay > 0 && delay > config.MaxDelay {
+			delay = config.MaxDelay
+		}
+	}
+	return delay
+}
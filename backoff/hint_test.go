@@ -0,0 +1,111 @@
+package backoff
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHint(t *testing.T) {
+	config := Config{
+		MinDelay:   time.Second,
+		MaxDelay:   time.Minute,
+		JitterMode: JitterNone,
+	}
+	cases := []struct {
+		name     string
+		mode     HintMode
+		hint     time.Duration
+		expected time.Duration
+	}{
+		{
+			name:     "clamp within range",
+			hint:     30 * time.Second,
+			expected: 30 * time.Second,
+		},
+		{
+			name:     "clamp to min",
+			hint:     time.Millisecond,
+			expected: time.Second,
+		},
+		{
+			name:     "clamp to max",
+			hint:     time.Hour,
+			expected: time.Minute,
+		},
+		{
+			name:     "override",
+			mode:     HintOverride,
+			hint:     time.Hour,
+			expected: time.Hour,
+		},
+		{
+			name:     "override negative",
+			mode:     HintOverride,
+			hint:     -time.Second,
+			expected: 0,
+		},
+	}
+	for _, policy := range []Policy{PolicyExponential, PolicyConstant, PolicyLinear, PolicyFibonacci, PolicyDecorrelatedJitter} {
+		for _, c := range cases {
+			cfg := config
+			cfg.Policy = policy
+			cfg.HintMode = c.mode
+			cfg.Rand = rand.NewSource(1)
+			s := New(cfg)
+			cfg.Rand = rand.NewSource(1)
+			unhinted := New(cfg)
+			unhinted.NextDelay()
+
+			s.(Hinter).Hint(c.hint)
+			assert.Equal(t, c.expected, s.NextDelay(), "%v %s", policy, c.name)
+			assert.Equal(t, unhinted.NextDelay(), s.NextDelay(), "%v %s: hint is used once", policy, c.name)
+		}
+	}
+}
+
+func TestHintExpBackoff(t *testing.T) {
+	eb := NewExpBackoffWithConfig(Config{
+		MinDelay:   time.Second,
+		MaxDelay:   time.Minute,
+		JitterMode: JitterNone,
+	})
+	assert.Equal(t, time.Second, eb.NextDelay())
+	eb.Hint(10 * time.Second)
+	assert.Equal(t, 10*time.Second, eb.NextDelay())
+	// the exponential increase goes on
+	assert.Equal(t, 4*time.Second, eb.NextDelay())
+}
+
+func TestRetryAfter(t *testing.T) {
+	var delays []time.Duration
+	s := New(Config{
+		MinDelay:   time.Millisecond,
+		MaxDelay:   time.Second,
+		JitterMode: JitterNone,
+		OnRetry: func(attempt uint64, delay time.Duration, err error) {
+			delays = append(delays, delay)
+		},
+	})
+	var attempts int
+	err := Retry(context.Background(), func() error {
+		attempts++
+		switch attempts {
+		case 1:
+			return RetryAfter(errFailure, 5*time.Millisecond)
+		case 2:
+			return errFailure
+		}
+		return nil
+	}, WithBackoff(s))
+	assert.NoError(t, err)
+	assert.Equal(t, []time.Duration{5 * time.Millisecond, 2 * time.Millisecond}, delays)
+
+	assert.Nil(t, RetryAfter(nil, time.Second))
+	assert.True(t, errors.Is(RetryAfter(errFailure, time.Second), errFailure))
+	assert.False(t, HintError(s, errFailure))
+}
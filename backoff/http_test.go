@@ -0,0 +1,72 @@
+package backoff
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2015, time.October, 21, 7, 28, 0, 0, time.UTC)
+	cases := []struct {
+		input    string
+		expected time.Duration
+		ok       bool
+	}{
+		{
+			input:    "120",
+			expected: 2 * time.Minute,
+			ok:       true,
+		},
+		{
+			input:    " 0 ",
+			expected: 0,
+			ok:       true,
+		},
+		{
+			input:    "Wed, 21 Oct 2015 07:30:00 GMT",
+			expected: 2 * time.Minute,
+			ok:       true,
+		},
+		{
+			input:    "Wednesday, 21-Oct-15 07:28:30 GMT",
+			expected: 30 * time.Second,
+			ok:       true,
+		},
+		{
+			input:    "Wed, 21 Oct 2015 07:00:00 GMT",
+			expected: 0,
+			ok:       true,
+		},
+		{
+			input: "",
+		},
+		{
+			input: "-1",
+		},
+		{
+			input: "1.5",
+		},
+		{
+			input: "99999999999999999999",
+		},
+		{
+			input: "tomorrow",
+		},
+	}
+	for _, c := range cases {
+		d, ok := ParseRetryAfter(c.input, now)
+		assert.Equal(t, c.ok, ok, c.input)
+		assert.Equal(t, c.expected, d, c.input)
+	}
+
+	resp := &http.Response{Header: http.Header{}}
+	resp.Header.Set("Retry-After", "3")
+	d, ok := ResponseRetryAfter(resp, now)
+	assert.True(t, ok)
+	assert.Equal(t, 3*time.Second, d)
+	_, ok = ResponseRetryAfter(nil, now)
+	assert.False(t, ok)
+}
@@ -0,0 +1,81 @@
+package backoff
+
+import (
+	"errors"
+	"math/rand"
+	"strings"
+	"time"
+)
+
+// JitterMode defines how a random jitter is applied to a delay.
+type JitterMode int
+
+const (
+	// JitterNormal adds a normally distributed jitter
+	// with standard deviation of Jitter times the delay.
+	JitterNormal JitterMode = iota
+	// JitterNone waits exactly the computed delay.
+	JitterNone
+	// JitterFull waits a random duration between zero and the delay.
+	JitterFull
+	// JitterEqual waits a half of the delay plus a random duration
+	// between zero and the other half.
+	JitterEqual
+)
+
+var jitterNames = []string{
+	JitterNormal: "normal",
+	JitterNone:   "none",
+	JitterFull:   "full",
+	JitterEqual:  "equal",
+}
+
+// String returns the name of the jitter mode.
+func (m JitterMode) String() string {
+	if m < 0 || int(m) >= len(jitterNames) {
+		return "unknown"
+	}
+	return jitterNames[m]
+}
+
+// Set parses the jitter mode name, so a JitterMode can be used as a flag.Value.
+func (m *JitterMode) Set(s string) error {
+	for i, name := range jitterNames {
+		if strings.EqualFold(s, name) {
+			*m = JitterMode(i)
+			return nil
+		}
+	}
+	return errors.New("unknown jitter mode " + s)
+}
+
+// jitter applies the jitter mode of config to the delay.
+// The result is never negative and never exceeds MaxDelay,
+// the normal jitter above MaxDelay is mirrored below it, so the delays do not pile up at MaxDelay.
+// It must be called under the lock of the strategy.
+func jitter(config Config, rnd *rand.Rand, delay time.Duration) time.Duration {
+	if delay <= 0 {
+		return 0
+	}
+	switch config.JitterMode {
+	case JitterNormal:
+		if config.Jitter != 0 {
+			delay += time.Duration(rnd.NormFloat64() * config.Jitter * float64(delay))
+			if config.MaxDelay > 0 && delay > config.MaxDelay {
+				delay = 2*config.MaxDelay - delay
+			}
+		}
+	case JitterFull:
+		delay = time.Duration(rnd.Int63n(int64(delay) + 1))
+	case JitterEqual:
+		half := delay / 2
+		delay = half + time.Duration(rnd.Int63n(int64(delay-half)+1))
+	}
+	if delay < 0 {
+		delay = 0
+	}
+	if config.MaxDelay > 0 && delay > config.MaxDelay {
+		delay = config.MaxDelay
+	}
+	return delay
+}
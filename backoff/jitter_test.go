@@ -0,0 +1,94 @@
+package backoff
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJitter(t *testing.T) {
+	delay := 10 * time.Second
+	cases := []struct {
+		name   string
+		config Config
+		min    time.Duration
+		max    time.Duration
+	}{
+		{
+			name:   "none",
+			config: Config{JitterMode: JitterNone, Jitter: 0.5, MaxDelay: time.Minute},
+			min:    delay,
+			max:    delay,
+		},
+		{
+			name:   "normal without factor",
+			config: Config{JitterMode: JitterNormal, MaxDelay: time.Minute},
+			min:    delay,
+			max:    delay,
+		},
+		{
+			name:   "normal",
+			config: Config{JitterMode: JitterNormal, Jitter: 2.0, MaxDelay: 15 * time.Second},
+			min:    0,
+			max:    15 * time.Second,
+		},
+		{
+			name:   "full",
+			config: Config{JitterMode: JitterFull, MaxDelay: time.Minute},
+			min:    0,
+			max:    delay,
+		},
+		{
+			name:   "equal",
+			config: Config{JitterMode: JitterEqual, MaxDelay: time.Minute},
+			min:    delay / 2,
+			max:    delay,
+		},
+		{
+			name:   "above max delay",
+			config: Config{JitterMode: JitterNone, MaxDelay: time.Second},
+			min:    time.Second,
+			max:    time.Second,
+		},
+	}
+	rnd := rand.New(rand.NewSource(1))
+	for _, c := range cases {
+		for i := 0; i < 1000; i++ {
+			d := jitter(c.config, rnd, delay)
+			if !assert.True(t, d >= c.min && d <= c.max, "%s: delay %v out of [%v, %v]", c.name, d, c.min, c.max) {
+				break
+			}
+		}
+	}
+}
+
+func TestJitterBelowMax(t *testing.T) {
+	config := Config{JitterMode: JitterNormal, Jitter: 0.1, MaxDelay: time.Minute}
+	rnd := rand.New(rand.NewSource(1))
+	var atMax, below int
+	for i := 0; i < 1000; i++ {
+		d := jitter(config, rnd, time.Minute)
+		assert.True(t, d <= time.Minute, d)
+		if d == time.Minute {
+			atMax++
+		}
+		if d < 54*time.Second {
+			below++
+		}
+	}
+	// the delays at MaxDelay are spread below it instead of clamping a half of them
+	assert.Equal(t, 0, atMax)
+	assert.True(t, below > 250 && below < 400, below)
+}
+
+func TestJitterMode(t *testing.T) {
+	for _, m := range []JitterMode{JitterNormal, JitterNone, JitterFull, JitterEqual} {
+		var parsed JitterMode
+		assert.NoError(t, parsed.Set(m.String()))
+		assert.Equal(t, m, parsed)
+	}
+	var m JitterMode
+	assert.Error(t, m.Set("random"))
+}
@@ -0,0 +1,77 @@
+package backoff
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// Metrics receives measurements of a strategy.
+// It must be safe for concurrent use.
+type Metrics interface {
+	// Retried is called with the chosen delay before every retry.
+	Retried(delay time.Duration)
+	// Waited is called with the time actually spent waiting for the delay.
+	Waited(d time.Duration)
+}
+
+// Counters is a Metrics which accumulates the measurements.
+// The zero value is ready to use.
+type Counters struct {
+	retries int64
+	delay   int64
+	waited  int64
+}
+
+// Retried implements Metrics.
+func (c *Counters) Retried(delay time.Duration) {
+	atomic.AddInt64(&c.retries, 1)
+	atomic.StoreInt64(&c.delay, int64(delay))
+}
+
+// Waited implements Metrics.
+func (c *Counters) Waited(d time.Duration) {
+	atomic.AddInt64(&c.waited, int64(d))
+}
+
+// Retries returns total number of retries.
+func (c *Counters) Retries() int64 {
+	return atomic.LoadInt64(&c.retries)
+}
+
+// Delay returns the last chosen delay.
+func (c *Counters) Delay() time.Duration {
+	return time.Duration(atomic.LoadInt64(&c.delay))
+}
+
+// TimeWaited returns total time spent backing off.
+func (c *Counters) TimeWaited() time.Duration {
+	return time.Duration(atomic.LoadInt64(&c.waited))
+}
+
+// counting is implemented by the strategies of this package to return
+// the number of attempts counted together with the delay.
+type counting interface {
+	nextDelay() (time.Duration, uint64)
+}
+
+// nextDelay returns the next delay of the strategy
+// and reports it to the hooks of config.
+func nextDelay(s Strategy, config Config, err error) time.Duration {
+	var (
+		delay    time.Duration
+		attempts uint64
+	)
+	if c, ok := s.(counting); ok {
+		delay, attempts = c.nextDelay()
+	} else {
+		delay = s.NextDelay()
+		attempts = s.Attempts()
+	}
+	if config.OnRetry != nil {
+		config.OnRetry(attempts, delay, err)
+	}
+	if config.Metrics != nil {
+		config.Metrics.Retried(delay)
+	}
+	return delay
+}
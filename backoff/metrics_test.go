@@ -0,0 +1,133 @@
+package backoff_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/austinov/go-recipes/backoff"
+	"github.com/austinov/go-recipes/backoff/backofftest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHooks(t *testing.T) {
+	var (
+		counters backoff.Counters
+		retries  []string
+	)
+	clock := backofftest.NewFakeClock(time.Unix(0, 0))
+	eb := backoff.NewExpBackoffWithConfig(backoff.Config{
+		MinDelay:   time.Second,
+		MaxDelay:   time.Minute,
+		JitterMode: backoff.JitterNone,
+		Clock:      clock,
+		OnRetry: func(attempt uint64, delay time.Duration, err error) {
+			retries = append(retries, fmt.Sprintf("%d %v %v", attempt, delay, err))
+		},
+		Metrics: &counters,
+	})
+
+	var attempts int
+	done := make(chan error)
+	go func() {
+		done <- backoff.Retry(context.Background(), func() error {
+			attempts++
+			if attempts < 4 {
+				return fmt.Errorf("failure %d", attempts)
+			}
+			return nil
+		}, backoff.WithBackoff(eb))
+	}()
+	for _, d := range []time.Duration{time.Second, 2 * time.Second, 4 * time.Second} {
+		clock.BlockUntil(1)
+		clock.Advance(d)
+	}
+	assert.NoError(t, <-done)
+	assert.Equal(t, []string{
+		"1 1s failure 1",
+		"2 2s failure 2",
+		"3 4s failure 3",
+	}, retries)
+	assert.Equal(t, int64(3), counters.Retries())
+	assert.Equal(t, 4*time.Second, counters.Delay())
+	assert.Equal(t, 7*time.Second, counters.TimeWaited())
+
+	// canceled waiting is measured too
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		done <- eb.Wait(ctx)
+	}()
+	clock.BlockUntil(1)
+	clock.Advance(3 * time.Second)
+	cancel()
+	assert.True(t, errors.Is(<-done, context.Canceled))
+	assert.Equal(t, "4 8s <nil>", retries[3])
+	assert.Equal(t, 10*time.Second, counters.TimeWaited())
+}
+
+func TestMetricsTimer(t *testing.T) {
+	var counters backoff.Counters
+	clock := backofftest.NewFakeClock(time.Unix(0, 0))
+	eb := backoff.NewExpBackoffWithConfig(backoff.Config{
+		MinDelay:   time.Second,
+		MaxDelay:   time.Minute,
+		JitterMode: backoff.JitterNone,
+		Clock:      clock,
+		Metrics:    &counters,
+	})
+
+	ch := eb.Delay()
+	clock.BlockUntil(1)
+	clock.Advance(time.Second)
+	assert.Equal(t, time.Unix(1, 0), <-ch)
+	assert.Equal(t, time.Second, counters.TimeWaited())
+
+	timer := eb.Timer()
+	clock.Advance(2 * time.Second)
+	<-timer.C()
+	assert.Equal(t, 3*time.Second, counters.TimeWaited())
+
+	// the waiting of a stopped timer is measured too
+	timer = eb.Timer()
+	clock.Advance(time.Second)
+	assert.True(t, timer.Stop())
+	assert.False(t, timer.Stop())
+	assert.Equal(t, 4*time.Second, counters.TimeWaited())
+	assert.Equal(t, int64(3), counters.Retries())
+}
+
+func TestHooksConcurrent(t *testing.T) {
+	var (
+		mu       sync.Mutex
+		attempts = make(map[uint64]time.Duration)
+	)
+	lb := backoff.NewLinearBackoff(backoff.Config{
+		MinDelay:   time.Millisecond,
+		MaxDelay:   time.Hour,
+		JitterMode: backoff.JitterNone,
+		OnRetry: func(attempt uint64, delay time.Duration, err error) {
+			mu.Lock()
+			attempts[attempt] = delay
+			mu.Unlock()
+		},
+	})
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 10; i++ {
+				backoff.NewTimer(lb).Stop()
+			}
+		}()
+	}
+	wg.Wait()
+	// every attempt is reported once with its own delay
+	assert.Len(t, attempts, 80)
+	for attempt, delay := range attempts {
+		assert.Equal(t, time.Duration(attempt)*time.Millisecond, delay, attempt)
+	}
+}
@@ -0,0 +1,142 @@
+package backoff
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+var (
+	// ErrMaxAttempts is the reason of RetryError when the attempts budget is exhausted.
+	ErrMaxAttempts = errors.New("max attempts reached")
+	// ErrMaxElapsedTime is the reason of RetryError when the time budget is exhausted.
+	ErrMaxElapsedTime = errors.New("max elapsed time reached")
+)
+
+// RetryError is returned by Retry when it gives up.
+// It wraps the last failure of the operation.
+type RetryError struct {
+	// Attempts is the number of times the operation was called.
+	Attempts uint64
+	// Reason explains why Retry stopped: ErrMaxAttempts, ErrMaxElapsedTime,
+	// ErrBudgetExhausted, the context error, or nil if the last failure was permanent.
+	Reason error
+	// Err is the last failure returned by the operation.
+	Err error
+}
+
+func (e *RetryError) Error() string {
+	if e.Reason == nil {
+		return fmt.Sprintf("backoff: permanent failure after %d attempts: %v", e.Attempts, e.Err)
+	}
+	return fmt.Sprintf("backoff: giving up after %d attempts (%v): %v", e.Attempts, e.Reason, e.Err)
+}
+
+// Unwrap returns the last failure of the operation and the reason if any,
+// so errors.Is matches both, e.g. context.Canceled and the failure.
+func (e *RetryError) Unwrap() []error {
+	if e.Reason == nil {
+		return []error{e.Err}
+	}
+	return []error{e.Err, e.Reason}
+}
+
+// PermanentError marks an error which must not be retried.
+type PermanentError struct {
+	Err error
+}
+
+func (e *PermanentError) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns the wrapped error.
+func (e *PermanentError) Unwrap() error {
+	return e.Err
+}
+
+// Permanent wraps err so that Retry stops immediately when the operation returns it.
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &PermanentError{Err: err}
+}
+
+type retryOptions struct {
+	backoff        Strategy
+	maxAttempts    uint64
+	maxElapsedTime time.Duration
+}
+
+// RetryOption configures Retry.
+type RetryOption func(*retryOptions)
+
+// WithBackoff sets the strategy to delay between attempts.
+// By default a new ExpBackoff with the default configuration is used.
+func WithBackoff(b Strategy) RetryOption {
+	return func(o *retryOptions) {
+		o.backoff = b
+	}
+}
+
+// WithMaxAttempts limits the number of times the operation is called.
+// Zero means no limit.
+func WithMaxAttempts(n uint64) RetryOption {
+	return func(o *retryOptions) {
+		o.maxAttempts = n
+	}
+}
+
+// WithMaxElapsedTime limits the total time spent in Retry.
+// Zero means no limit.
+func WithMaxElapsedTime(d time.Duration) RetryOption {
+	return func(o *retryOptions) {
+		o.maxElapsedTime = d
+	}
+}
+
+// Retry calls op until it succeeds, returns a permanent error,
+// the context is done or the attempts/time/retry budget is exhausted.
+// In the case of giving up it returns *RetryError wrapping the last failure.
+// Successful calls are deposited to the retry budget of the strategy.
+// The delay of an error wrapped by RetryAfter is passed to the strategy as a hint.
+func Retry(ctx context.Context, op func() error, opts ...RetryOption) error {
+	o := retryOptions{}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.backoff == nil {
+		o.backoff = NewExpBackoff()
+	}
+
+	config := settingsOf(o.backoff)
+	start := config.Clock.Now()
+	var attempts uint64
+	for {
+		if err := ctx.Err(); err != nil {
+			return &RetryError{Attempts: attempts, Reason: err, Err: err}
+		}
+		attempts++
+		err := op()
+		if err == nil {
+			config.Budget.Deposit()
+			return nil
+		}
+		var perm *PermanentError
+		if errors.As(err, &perm) {
+			return &RetryError{Attempts: attempts, Err: perm.Err}
+		}
+		HintError(o.backoff, err)
+		if o.maxAttempts > 0 && attempts >= o.maxAttempts {
+			return &RetryError{Attempts: attempts, Reason: ErrMaxAttempts, Err: err}
+		}
+		if o.maxElapsedTime > 0 && config.Clock.Now().Sub(start) >= o.maxElapsedTime {
+			return &RetryError{Attempts: attempts, Reason: ErrMaxElapsedTime, Err: err}
+		}
+		if werr := wait(ctx, o.backoff, err); werr != nil {
+			return &RetryError{Attempts: attempts, Reason: werr, Err: err}
+		}
+	}
+}
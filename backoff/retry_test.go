@@ -0,0 +1,141 @@
+package backoff
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var errFailure = errors.New("failure")
+
+func TestRetry(t *testing.T) {
+	cases := []struct {
+		name     string
+		failures int
+		opts     []RetryOption
+		attempts uint64
+		reason   error
+		err      error
+	}{
+		{
+			name:     "success at once",
+			failures: 0,
+			attempts: 1,
+		},
+		{
+			name:     "success after failures",
+			failures: 3,
+			attempts: 4,
+		},
+		{
+			name:     "max attempts",
+			failures: 10,
+			opts:     []RetryOption{WithMaxAttempts(3)},
+			attempts: 3,
+			reason:   ErrMaxAttempts,
+			err:      errFailure,
+		},
+		{
+			name:     "max elapsed time",
+			failures: 1000,
+			opts:     []RetryOption{WithMaxElapsedTime(20 * time.Millisecond)},
+			reason:   ErrMaxElapsedTime,
+			err:      errFailure,
+		},
+	}
+	for _, c := range cases {
+		var attempts uint64
+		op := func() error {
+			attempts++
+			if attempts <= uint64(c.failures) {
+				return errFailure
+			}
+			return nil
+		}
+		eb := NewExpBackoffWithConfig(Config{
+			MinDelay: time.Millisecond,
+			MaxDelay: 5 * time.Millisecond,
+			Expo:     2.0,
+		})
+		opts := append([]RetryOption{WithBackoff(eb)}, c.opts...)
+		err := Retry(context.Background(), op, opts...)
+		if c.err == nil {
+			assert.NoError(t, err, c.name)
+			assert.Equal(t, c.attempts, attempts, c.name)
+			continue
+		}
+		var re *RetryError
+		if assert.True(t, errors.As(err, &re), c.name) {
+			assert.Equal(t, c.reason, re.Reason, c.name)
+			assert.Equal(t, attempts, re.Attempts, c.name)
+			if c.attempts > 0 {
+				assert.Equal(t, c.attempts, re.Attempts, c.name)
+			}
+		}
+		assert.True(t, errors.Is(err, c.err), c.name)
+	}
+}
+
+func TestRetryPermanent(t *testing.T) {
+	eb := NewExpBackoffWithConfig(Config{
+		MinDelay: time.Millisecond,
+		MaxDelay: 5 * time.Millisecond,
+		Expo:     2.0,
+	})
+	var attempts uint64
+	err := Retry(context.Background(), func() error {
+		attempts++
+		if attempts == 2 {
+			return Permanent(errFailure)
+		}
+		return errors.New("temporary")
+	}, WithBackoff(eb))
+
+	var re *RetryError
+	if assert.True(t, errors.As(err, &re)) {
+		assert.Nil(t, re.Reason)
+		assert.Equal(t, uint64(2), re.Attempts)
+	}
+	assert.True(t, errors.Is(err, errFailure))
+}
+
+func TestRetryContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	var attempts uint64
+	err := Retry(ctx, func() error {
+		attempts++
+		if attempts == 2 {
+			cancel()
+		}
+		return errFailure
+	})
+
+	var re *RetryError
+	if assert.True(t, errors.As(err, &re)) {
+		assert.Equal(t, context.Canceled, re.Reason)
+		assert.Equal(t, uint64(2), re.Attempts)
+	}
+	assert.True(t, errors.Is(err, errFailure))
+}
+
+func TestRetryContextWait(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	b := NewExpBackoffWithConfig(Config{MinDelay: time.Hour, MaxDelay: time.Hour})
+	err := Retry(ctx, func() error {
+		// the context is canceled while Retry waits for the next attempt
+		time.AfterFunc(10*time.Millisecond, cancel)
+		return errFailure
+	}, WithBackoff(b))
+
+	var re *RetryError
+	if assert.True(t, errors.As(err, &re)) {
+		assert.Equal(t, uint64(1), re.Attempts)
+	}
+	assert.True(t, errors.Is(err, context.Canceled))
+	assert.True(t, errors.Is(err, errFailure))
+	assert.False(t, errors.Is(err, ErrMaxAttempts))
+}
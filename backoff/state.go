@@ -0,0 +1,142 @@
+package backoff
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+// stateVersion is the version of the binary encoding of State.
+const stateVersion = 1
+
+// stateSize is the size of the binary encoding of State.
+const stateSize = 1 + 8 + 8 + 8
+
+// ErrInvalidState is returned when the encoded state can not be decoded.
+var ErrInvalidState = errors.New("backoff: invalid state")
+
+// State is the state of ExpBackoff which can be saved
+// to resume the backoff after the restart of the process.
+type State struct {
+	// Delay is the delay of the next attempt without jitter
+	Delay time.Duration `json:"delay"`
+	// Attempts is the number of unsuccessful attempts
+	Attempts uint64 `json:"attempts"`
+	// LastAttempt is the time of the last attempt which is the time of the last NextDelay
+	// plus the returned delay, zero if there were no attempts
+	LastAttempt time.Time `json:"last_attempt"`
+}
+
+// MarshalBinary encodes the state into a binary form.
+func (s State) MarshalBinary() ([]byte, error) {
+	data := make([]byte, stateSize)
+	data[0] = stateVersion
+	binary.BigEndian.PutUint64(data[1:], uint64(s.Delay))
+	binary.BigEndian.PutUint64(data[9:], s.Attempts)
+	var last int64
+	if !s.LastAttempt.IsZero() {
+		last = s.LastAttempt.UnixNano()
+	}
+	binary.BigEndian.PutUint64(data[17:], uint64(last))
+	return data, nil
+}
+
+// UnmarshalBinary decodes the state from the binary form.
+func (s *State) UnmarshalBinary(data []byte) error {
+	if len(data) != stateSize || data[0] != stateVersion {
+		return ErrInvalidState
+	}
+	delay := time.Duration(binary.BigEndian.Uint64(data[1:]))
+	if delay < 0 {
+		return ErrInvalidState
+	}
+	s.Delay = delay
+	s.Attempts = binary.BigEndian.Uint64(data[9:])
+	s.LastAttempt = time.Time{}
+	if last := int64(binary.BigEndian.Uint64(data[17:])); last != 0 {
+		s.LastAttempt = time.Unix(0, last)
+	}
+	return nil
+}
+
+// State returns the current state of the backoff.
+func (e *ExpBackoff) State() State {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return State{
+		Delay:       e.delay,
+		Attempts:    e.attempts,
+		LastAttempt: e.lastAttempt,
+	}
+}
+
+// Restore sets the state of the backoff, the delay is clamped to MinDelay and MaxDelay.
+// The delay relaxes toward MinDelay by Decay according to the time passed since LastAttempt.
+func (e *ExpBackoff) Restore(s State) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.delay = s.Delay
+	if e.delay < e.config.MinDelay {
+		e.delay = e.config.MinDelay
+	}
+	if e.delay > e.config.MaxDelay {
+		e.delay = e.config.MaxDelay
+	}
+	e.attempts = s.Attempts
+	e.lastAttempt = s.LastAttempt
+	e.decay(e.config.Clock.Now())
+}
+
+// MarshalBinary encodes the state of the backoff into a binary form.
+func (e *ExpBackoff) MarshalBinary() ([]byte, error) {
+	return e.State().MarshalBinary()
+}
+
+// UnmarshalBinary restores the state of the backoff from the binary form.
+func (e *ExpBackoff) UnmarshalBinary(data []byte) error {
+	var s State
+	if err := s.UnmarshalBinary(data); err != nil {
+		return err
+	}
+	e.Restore(s)
+	return nil
+}
+
+// MarshalJSON encodes the state of the backoff into JSON.
+func (e *ExpBackoff) MarshalJSON() ([]byte, error) {
+	return json.Marshal(e.State())
+}
+
+// UnmarshalJSON restores the state of the backoff from JSON.
+func (e *ExpBackoff) UnmarshalJSON(data []byte) error {
+	var s State
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	e.Restore(s)
+	return nil
+}
+
+// decay divides the delay by Expo for every Decay interval passed since the last attempt,
+// the waiting for the delay before it is not counted as idle.
+// The number of attempts is reset when the delay relaxes to MinDelay.
+// It must be called under the lock.
+func (e *ExpBackoff) decay(now time.Time) {
+	if e.config.Decay <= 0 || e.config.Expo <= 1 || e.lastAttempt.IsZero() {
+		return
+	}
+	steps := now.Sub(e.lastAttempt) / e.config.Decay
+	if steps <= 0 {
+		return
+	}
+	// the passed intervals must not be applied again
+	e.lastAttempt = e.lastAttempt.Add(steps * e.config.Decay)
+	for ; steps > 0 && e.delay > e.config.MinDelay; steps-- {
+		e.delay = time.Duration(float64(e.delay) / e.config.Expo)
+	}
+	if e.delay <= e.config.MinDelay {
+		e.delay = e.config.MinDelay
+		e.attempts = 0
+	}
+}
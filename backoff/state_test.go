@@ -0,0 +1,140 @@
+package backoff_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/austinov/go-recipes/backoff"
+	"github.com/austinov/go-recipes/backoff/backofftest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStateRoundTrip(t *testing.T) {
+	clock := backofftest.NewFakeClock(time.Unix(1000, 0))
+	config := backoff.Config{
+		MinDelay:   100 * time.Millisecond,
+		MaxDelay:   10 * time.Second,
+		Expo:       2.0,
+		JitterMode: backoff.JitterNone,
+		Decay:      time.Minute,
+		Clock:      clock,
+	}
+	eb := backoff.NewExpBackoffWithConfig(config)
+	for i := 0; i < 3; i++ {
+		eb.NextDelay()
+	}
+	expected := backoff.State{
+		Delay:       800 * time.Millisecond,
+		Attempts:    3,
+		LastAttempt: time.Unix(1000, 0).Add(400 * time.Millisecond),
+	}
+	assert.Equal(t, expected, eb.State())
+
+	data, err := eb.MarshalBinary()
+	assert.NoError(t, err)
+	restored := backoff.NewExpBackoffWithConfig(config)
+	assert.NoError(t, restored.UnmarshalBinary(data))
+	assert.True(t, restored.State().LastAttempt.Equal(expected.LastAttempt))
+	assert.Equal(t, expected.Attempts, restored.Attempts())
+	assert.Equal(t, expected.Delay, restored.NextDelay())
+
+	data, err = json.Marshal(eb)
+	assert.NoError(t, err)
+	restored = backoff.NewExpBackoffWithConfig(config)
+	assert.NoError(t, json.Unmarshal(data, restored))
+	assert.Equal(t, expected.Attempts, restored.Attempts())
+	assert.Equal(t, expected.Delay, restored.NextDelay())
+}
+
+func TestStateInvalid(t *testing.T) {
+	eb := backoff.NewExpBackoffWithConfig(backoff.Config{})
+	assert.Equal(t, backoff.ErrInvalidState, eb.UnmarshalBinary(nil))
+	assert.Equal(t, backoff.ErrInvalidState, eb.UnmarshalBinary(make([]byte, 25)))
+	assert.Error(t, eb.UnmarshalJSON([]byte("{")))
+}
+
+func TestStateDecay(t *testing.T) {
+	clock := backofftest.NewFakeClock(time.Unix(1000, 0))
+	state := backoff.State{
+		Delay:       6400 * time.Millisecond,
+		Attempts:    7,
+		LastAttempt: time.Unix(1000, 0),
+	}
+	cases := []struct {
+		name     string
+		idle     time.Duration
+		delay    time.Duration
+		attempts uint64
+	}{
+		{
+			name:     "no idle",
+			delay:    6400 * time.Millisecond,
+			attempts: 7,
+		},
+		{
+			name:     "less than decay",
+			idle:     59 * time.Second,
+			delay:    6400 * time.Millisecond,
+			attempts: 7,
+		},
+		{
+			name:     "two intervals",
+			idle:     2*time.Minute + 30*time.Second,
+			delay:    1600 * time.Millisecond,
+			attempts: 7,
+		},
+		{
+			name:     "relaxed to min",
+			idle:     time.Hour,
+			delay:    100 * time.Millisecond,
+			attempts: 0,
+		},
+	}
+	for _, c := range cases {
+		clock := backofftest.NewFakeClock(clock.Now().Add(c.idle))
+		eb := backoff.NewExpBackoffWithConfig(backoff.Config{
+			MinDelay:   100 * time.Millisecond,
+			MaxDelay:   10 * time.Second,
+			Expo:       2.0,
+			JitterMode: backoff.JitterNone,
+			Decay:      time.Minute,
+			Clock:      clock,
+		})
+		eb.Restore(state)
+		assert.Equal(t, c.attempts, eb.Attempts(), c.name)
+		assert.Equal(t, c.delay, eb.NextDelay(), c.name)
+	}
+
+	// the delay of a live backoff grows while it waits longer than Decay
+	clock = backofftest.NewFakeClock(time.Unix(1000, 0))
+	eb := backoff.NewExpBackoffWithConfig(backoff.Config{
+		MinDelay:   10 * time.Second,
+		MaxDelay:   10 * time.Minute,
+		Expo:       2.0,
+		JitterMode: backoff.JitterNone,
+		Decay:      time.Minute,
+		Clock:      clock,
+	})
+	var delays []time.Duration
+	for i := 0; i < 8; i++ {
+		d := eb.NextDelay()
+		delays = append(delays, d)
+		clock.Advance(d)
+	}
+	assert.Equal(t, []time.Duration{
+		10 * time.Second,
+		20 * time.Second,
+		40 * time.Second,
+		80 * time.Second,
+		160 * time.Second,
+		320 * time.Second,
+		10 * time.Minute,
+		10 * time.Minute,
+	}, delays)
+
+	// and decays when it is idle after the last wait
+	clock.Advance(time.Minute)
+	assert.Equal(t, 5*time.Minute, eb.NextDelay())
+	assert.Equal(t, 10*time.Minute, eb.NextDelay())
+}
@@ -0,0 +1,365 @@
+package backoff
+
+import (
+	"errors"
+	"math/rand"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Strategy is a policy to compute the delay between repeated attempts.
+type Strategy interface {
+	// NextDelay returns the delay before the next attempt
+	// and counts the attempt as unsuccessful.
+	NextDelay() time.Duration
+	// Reset sets the strategy to its initial state.
+	Reset()
+	// Attempts returns number of unsuccessful attempts.
+	Attempts() uint64
+}
+
+// Policy defines which Strategy is built by New.
+type Policy int
+
+const (
+	// PolicyExponential multiplies the delay by Expo after each attempt.
+	PolicyExponential Policy = iota
+	// PolicyConstant always waits MinDelay.
+	PolicyConstant
+	// PolicyLinear increases the delay by Step after each attempt.
+	PolicyLinear
+	// PolicyFibonacci increases the delay along the Fibonacci sequence of MinDelay.
+	PolicyFibonacci
+	// PolicyDecorrelatedJitter picks a random delay between MinDelay
+	// and Expo times the previous delay (AWS "decorrelated jitter").
+	PolicyDecorrelatedJitter
+)
+
+var policyNames = []string{
+	PolicyExponential:        "exponential",
+	PolicyConstant:           "constant",
+	PolicyLinear:             "linear",
+	PolicyFibonacci:          "fibonacci",
+	PolicyDecorrelatedJitter: "decorrelated",
+}
+
+// String returns the name of the policy.
+func (p Policy) String() string {
+	if p < 0 || int(p) >= len(policyNames) {
+		return "unknown"
+	}
+	return policyNames[p]
+}
+
+// Set parses the policy name, so a Policy can be used as a flag.Value.
+func (p *Policy) Set(s string) error {
+	for i, name := range policyNames {
+		if strings.EqualFold(s, name) {
+			*p = Policy(i)
+			return nil
+		}
+	}
+	return errors.New("unknown backoff policy " + s)
+}
+
+// New returns a Strategy according to the Policy of passed configuration.
+func New(config Config) Strategy {
+	switch config.Policy {
+	case PolicyConstant:
+		return NewConstantBackoff(config)
+	case PolicyLinear:
+		return NewLinearBackoff(config)
+	case PolicyFibonacci:
+		return NewFibonacciBackoff(config)
+	case PolicyDecorrelatedJitter:
+		return NewDecorrelatedJitterBackoff(config)
+	default:
+		return NewExpBackoffWithConfig(config)
+	}
+}
+
+// ConstantBackoff is a thread-safe Strategy which always waits MinDelay.
+type ConstantBackoff struct {
+	config Config
+
+	// this mutex protects attempts, rnd and hint
+	mu       sync.Mutex
+	attempts uint64
+	rnd      *rand.Rand
+	hint     hint
+}
+
+// NewConstantBackoff returns a ConstantBackoff by passed configuration.
+func NewConstantBackoff(config Config) *ConstantBackoff {
+	config = withDefaults(config)
+	return &ConstantBackoff{
+		config: config,
+		rnd:    rand.New(config.Rand),
+	}
+}
+
+// NextDelay returns MinDelay.
+func (c *ConstantBackoff) NextDelay() time.Duration {
+	delay, _ := c.nextDelay()
+	return delay
+}
+
+func (c *ConstantBackoff) nextDelay() (time.Duration, uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.attempts++
+	return c.hint.take(c.config, jitter(c.config, c.rnd, c.config.MinDelay)), c.attempts
+}
+
+// Reset sets number of attempts to zero.
+func (c *ConstantBackoff) Reset() {
+	c.mu.Lock()
+	c.attempts = 0
+	c.mu.Unlock()
+}
+
+// Hint sets the delay of the next attempt according to HintMode.
+func (c *ConstantBackoff) Hint(d time.Duration) {
+	c.mu.Lock()
+	c.hint = hint{delay: d, set: true}
+	c.mu.Unlock()
+}
+
+// Attempts returns number of unsuccessful attempts
+func (c *ConstantBackoff) Attempts() uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.attempts
+}
+
+func (c *ConstantBackoff) settings() Config {
+	return c.config
+}
+
+// LinearBackoff is a thread-safe Strategy which increases
+// the delay by Step after each attempt up to MaxDelay.
+type LinearBackoff struct {
+	config Config
+
+	// this mutex protects delay, attempts, rnd and hint
+	mu       sync.Mutex
+	delay    time.Duration
+	attempts uint64
+	rnd      *rand.Rand
+	hint     hint
+}
+
+// NewLinearBackoff returns a LinearBackoff by passed configuration.
+// If Step is omitted MinDelay is used.
+func NewLinearBackoff(config Config) *LinearBackoff {
+	if config.MaxDelay == 0 {
+		config.MaxDelay = DefaultConfig.MaxDelay
+	}
+	if config.Step == 0 {
+		config.Step = config.MinDelay
+	}
+	config = withDefaults(config)
+	return &LinearBackoff{
+		config: config,
+		delay:  config.MinDelay,
+		rnd:    rand.New(config.Rand),
+	}
+}
+
+// NextDelay returns linearly increased delay.
+func (l *LinearBackoff) NextDelay() time.Duration {
+	delay, _ := l.nextDelay()
+	return delay
+}
+
+func (l *LinearBackoff) nextDelay() (time.Duration, uint64) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	currDelay := l.delay
+	l.delay += l.config.Step
+	if l.delay > l.config.MaxDelay {
+		l.delay = l.config.MaxDelay
+	}
+	l.attempts++
+	return l.hint.take(l.config, jitter(l.config, l.rnd, currDelay)), l.attempts
+}
+
+// Reset sets current delay the minimum value
+func (l *LinearBackoff) Reset() {
+	l.mu.Lock()
+	l.attempts = 0
+	l.delay = l.config.MinDelay
+	l.mu.Unlock()
+}
+
+// Hint sets the delay of the next attempt according to HintMode.
+func (l *LinearBackoff) Hint(d time.Duration) {
+	l.mu.Lock()
+	l.hint = hint{delay: d, set: true}
+	l.mu.Unlock()
+}
+
+// Attempts returns number of unsuccessful attempts
+func (l *LinearBackoff) Attempts() uint64 {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.attempts
+}
+
+func (l *LinearBackoff) settings() Config {
+	return l.config
+}
+
+// FibonacciBackoff is a thread-safe Strategy which increases the delay
+// along the Fibonacci sequence (1, 1, 2, 3, 5...) of MinDelay up to MaxDelay.
+type FibonacciBackoff struct {
+	config Config
+
+	// this mutex protects delays, attempts, rnd and hint
+	mu       sync.Mutex
+	prev     time.Duration
+	delay    time.Duration
+	attempts uint64
+	rnd      *rand.Rand
+	hint     hint
+}
+
+// NewFibonacciBackoff returns a FibonacciBackoff by passed configuration.
+func NewFibonacciBackoff(config Config) *FibonacciBackoff {
+	if config.MaxDelay == 0 {
+		config.MaxDelay = DefaultConfig.MaxDelay
+	}
+	config = withDefaults(config)
+	return &FibonacciBackoff{
+		config: config,
+		delay:  config.MinDelay,
+		rnd:    rand.New(config.Rand),
+	}
+}
+
+// NextDelay returns the delay increased along the Fibonacci sequence.
+func (f *FibonacciBackoff) NextDelay() time.Duration {
+	delay, _ := f.nextDelay()
+	return delay
+}
+
+func (f *FibonacciBackoff) nextDelay() (time.Duration, uint64) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	currDelay := f.delay
+	f.prev, f.delay = f.delay, f.prev+f.delay
+	if f.delay > f.config.MaxDelay {
+		f.delay = f.config.MaxDelay
+	}
+	f.attempts++
+	return f.hint.take(f.config, jitter(f.config, f.rnd, currDelay)), f.attempts
+}
+
+// Reset sets current delay the minimum value
+func (f *FibonacciBackoff) Reset() {
+	f.mu.Lock()
+	f.attempts = 0
+	f.prev = 0
+	f.delay = f.config.MinDelay
+	f.mu.Unlock()
+}
+
+// Hint sets the delay of the next attempt according to HintMode.
+func (f *FibonacciBackoff) Hint(d time.Duration) {
+	f.mu.Lock()
+	f.hint = hint{delay: d, set: true}
+	f.mu.Unlock()
+}
+
+// Attempts returns number of unsuccessful attempts
+func (f *FibonacciBackoff) Attempts() uint64 {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.attempts
+}
+
+func (f *FibonacciBackoff) settings() Config {
+	return f.config
+}
+
+// DecorrelatedJitterBackoff is a thread-safe Strategy which picks a random
+// delay between MinDelay and Expo times the previous delay up to MaxDelay.
+// JitterMode is not applied because the delay is random itself.
+// See https://www.awsarchitectureblog.com/2015/03/backoff.html
+type DecorrelatedJitterBackoff struct {
+	config Config
+
+	// this mutex protects delay, attempts, rnd and hint
+	mu       sync.Mutex
+	delay    time.Duration
+	attempts uint64
+	rnd      *rand.Rand
+	hint     hint
+}
+
+// NewDecorrelatedJitterBackoff returns a DecorrelatedJitterBackoff by passed configuration.
+func NewDecorrelatedJitterBackoff(config Config) *DecorrelatedJitterBackoff {
+	if config.MaxDelay == 0 {
+		config.MaxDelay = DefaultConfig.MaxDelay
+	}
+	if config.Expo == 0 {
+		config.Expo = 3.0
+	}
+	config = withDefaults(config)
+	return &DecorrelatedJitterBackoff{
+		config: config,
+		delay:  config.MinDelay,
+		rnd:    rand.New(config.Rand),
+	}
+}
+
+// NextDelay returns a random delay based on the previous one.
+func (d *DecorrelatedJitterBackoff) NextDelay() time.Duration {
+	delay, _ := d.nextDelay()
+	return delay
+}
+
+func (d *DecorrelatedJitterBackoff) nextDelay() (time.Duration, uint64) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.attempts++
+	if d.attempts > 1 {
+		upper := time.Duration(float64(d.delay) * d.config.Expo)
+		if upper > d.config.MaxDelay {
+			upper = d.config.MaxDelay
+		}
+		if upper > d.config.MinDelay {
+			d.delay = d.config.MinDelay + time.Duration(d.rnd.Int63n(int64(upper-d.config.MinDelay)))
+		} else {
+			d.delay = upper
+		}
+	}
+	return d.hint.take(d.config, d.delay), d.attempts
+}
+
+// Reset sets current delay the minimum value
+func (d *DecorrelatedJitterBackoff) Reset() {
+	d.mu.Lock()
+	d.attempts = 0
+	d.delay = d.config.MinDelay
+	d.mu.Unlock()
+}
+
+// Hint sets the delay of the next attempt according to HintMode.
+func (d *DecorrelatedJitterBackoff) Hint(delay time.Duration) {
+	d.mu.Lock()
+	d.hint = hint{delay: delay, set: true}
+	d.mu.Unlock()
+}
+
+// Attempts returns number of unsuccessful attempts
+func (d *DecorrelatedJitterBackoff) Attempts() uint64 {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.attempts
+}
+
+func (d *DecorrelatedJitterBackoff) settings() Config {
+	return d.config
+}
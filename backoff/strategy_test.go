@@ -0,0 +1,95 @@
+package backoff
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStrategies(t *testing.T) {
+	config := Config{
+		MinDelay: 100 * time.Millisecond,
+		MaxDelay: 1 * time.Second,
+		Expo:     2.0,
+	}
+	ms := func(ds ...int) []time.Duration {
+		res := make([]time.Duration, len(ds))
+		for i, d := range ds {
+			res[i] = time.Duration(d) * time.Millisecond
+		}
+		return res
+	}
+	cases := []struct {
+		policy   Policy
+		step     time.Duration
+		expected []time.Duration
+	}{
+		{
+			policy:   PolicyExponential,
+			expected: ms(100, 200, 400, 800, 1000, 1000),
+		},
+		{
+			policy:   PolicyConstant,
+			expected: ms(100, 100, 100, 100, 100, 100),
+		},
+		{
+			policy:   PolicyLinear,
+			expected: ms(100, 200, 300, 400, 500, 600),
+		},
+		{
+			policy:   PolicyLinear,
+			step:     300 * time.Millisecond,
+			expected: ms(100, 400, 700, 1000, 1000, 1000),
+		},
+		{
+			policy:   PolicyFibonacci,
+			expected: ms(100, 100, 200, 300, 500, 800, 1000, 1000),
+		},
+	}
+	for _, c := range cases {
+		cfg := config
+		cfg.Policy = c.policy
+		cfg.Step = c.step
+		s := New(cfg)
+		for round := 0; round < 2; round++ {
+			for i, expected := range c.expected {
+				assert.Equal(t, expected, s.NextDelay(), "%v attempt %d", c.policy, i+1)
+			}
+			assert.Equal(t, uint64(len(c.expected)), s.Attempts(), c.policy.String())
+			s.Reset()
+			assert.Equal(t, uint64(0), s.Attempts(), c.policy.String())
+		}
+	}
+}
+
+func TestDecorrelatedJitter(t *testing.T) {
+	config := Config{
+		Policy:   PolicyDecorrelatedJitter,
+		MinDelay: 100 * time.Millisecond,
+		MaxDelay: 1 * time.Second,
+	}
+	s := New(config)
+	assert.IsType(t, &DecorrelatedJitterBackoff{}, s)
+	assert.Equal(t, config.MinDelay, s.NextDelay())
+	prev := config.MinDelay
+	for i := 0; i < 100; i++ {
+		d := s.NextDelay()
+		upper := 3 * prev
+		if upper > config.MaxDelay {
+			upper = config.MaxDelay
+		}
+		assert.True(t, d >= config.MinDelay && d <= upper, "delay %v out of [%v, %v]", d, config.MinDelay, upper)
+		prev = d
+	}
+}
+
+func TestPolicy(t *testing.T) {
+	for _, p := range []Policy{PolicyExponential, PolicyConstant, PolicyLinear, PolicyFibonacci, PolicyDecorrelatedJitter} {
+		var parsed Policy
+		assert.NoError(t, parsed.Set(p.String()))
+		assert.Equal(t, p, parsed)
+	}
+	var p Policy
+	assert.Error(t, p.Set("quadratic"))
+}
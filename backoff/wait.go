@@ -0,0 +1,119 @@
+package backoff
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// denied is the closed channel returned instead of a delay when the retry budget denies the retry.
+var denied = func() chan time.Time {
+	c := make(chan time.Time)
+	close(c)
+	return c
+}()
+
+// deniedTimer is the timer returned when the retry budget denies the retry.
+type deniedTimer struct{}
+
+func (deniedTimer) C() <-chan time.Time {
+	return denied
+}
+
+func (deniedTimer) Stop() bool {
+	return false
+}
+
+// NewTimer returns a stoppable timer which fires after the next delay of the strategy.
+// The caller should stop the timer if it gives up waiting.
+// If the retry budget denies the retry, the channel of the timer is closed
+// without a value, so the receive reports false.
+func NewTimer(s Strategy) Timer {
+	config := settingsOf(s)
+	if !config.Budget.Withdraw() {
+		return deniedTimer{}
+	}
+	return newTimer(config, nextDelay(s, config, nil))
+}
+
+// newTimer returns a timer of the clock of config which reports the time
+// spent waiting to Metrics when it fires or is stopped.
+func newTimer(config Config, delay time.Duration) Timer {
+	if config.Metrics == nil {
+		return config.Clock.NewTimer(delay)
+	}
+	start := config.Clock.Now()
+	t := &measuredTimer{
+		Timer: config.Clock.NewTimer(delay),
+		c:     make(chan time.Time, 1),
+		stop:  make(chan struct{}),
+	}
+	t.waited = func() {
+		config.Metrics.Waited(config.Clock.Now().Sub(start))
+	}
+	go func() {
+		select {
+		case now := <-t.Timer.C():
+			t.waited()
+			t.c <- now
+		case <-t.stop:
+		}
+	}()
+	return t
+}
+
+// measuredTimer forwards the time of the timer after reporting the waiting to Metrics.
+type measuredTimer struct {
+	Timer
+	c      chan time.Time
+	stop   chan struct{}
+	once   sync.Once
+	waited func()
+}
+
+func (t *measuredTimer) C() <-chan time.Time {
+	return t.c
+}
+
+// Stop stops the timer and reports the waiting if the timer has not fired.
+func (t *measuredTimer) Stop() bool {
+	stopped := t.Timer.Stop()
+	if stopped {
+		t.once.Do(func() {
+			close(t.stop)
+			t.waited()
+		})
+	}
+	return stopped
+}
+
+// Wait waits for the next delay of the strategy.
+// It returns the context error if the context is done earlier,
+// the timer is stopped in that case.
+// It returns ErrBudgetExhausted without waiting if the retry budget denies the retry.
+func Wait(ctx context.Context, s Strategy) error {
+	return wait(ctx, s, nil)
+}
+
+// wait waits for the next delay of the strategy reporting the last error to the hooks.
+func wait(ctx context.Context, s Strategy, lastErr error) error {
+	config := settingsOf(s)
+	if !config.Budget.Withdraw() {
+		return ErrBudgetExhausted
+	}
+	delay := nextDelay(s, config, lastErr)
+	start := config.Clock.Now()
+	t := config.Clock.NewTimer(delay)
+	defer func() {
+		t.Stop()
+		if config.Metrics != nil {
+			config.Metrics.Waited(config.Clock.Now().Sub(start))
+		}
+	}()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C():
+		return nil
+	}
+}
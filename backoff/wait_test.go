@@ -0,0 +1,74 @@
+package backoff_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/austinov/go-recipes/backoff"
+	"github.com/austinov/go-recipes/backoff/backofftest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWait(t *testing.T) {
+	clock := backofftest.NewFakeClock(time.Unix(0, 0))
+	eb := backoff.NewExpBackoffWithConfig(backoff.Config{
+		MinDelay:   time.Second,
+		MaxDelay:   time.Minute,
+		JitterMode: backoff.JitterNone,
+		Clock:      clock,
+	})
+	done := make(chan error)
+	go func() {
+		done <- eb.Wait(context.Background())
+	}()
+	clock.BlockUntil(1)
+	clock.Advance(time.Second)
+	assert.NoError(t, <-done)
+	assert.Equal(t, uint64(1), eb.Attempts())
+}
+
+func TestWaitCancel(t *testing.T) {
+	clock := backofftest.NewFakeClock(time.Unix(0, 0))
+	eb := backoff.NewExpBackoffWithConfig(backoff.Config{
+		MinDelay:   time.Second,
+		MaxDelay:   time.Minute,
+		JitterMode: backoff.JitterNone,
+		Clock:      clock,
+	})
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		done <- eb.Wait(ctx)
+	}()
+	clock.BlockUntil(1)
+	cancel()
+	assert.Equal(t, context.Canceled, <-done)
+	assert.Equal(t, 0, clock.Timers(), "timer must be stopped")
+}
+
+func TestTimer(t *testing.T) {
+	clock := backofftest.NewFakeClock(time.Unix(0, 0))
+	eb := backoff.NewExpBackoffWithConfig(backoff.Config{
+		MinDelay:   time.Second,
+		MaxDelay:   time.Minute,
+		JitterMode: backoff.JitterNone,
+		Clock:      clock,
+	})
+
+	timer := eb.Timer()
+	clock.Advance(time.Second)
+	assert.Equal(t, time.Unix(1, 0), <-timer.C())
+	assert.False(t, timer.Stop())
+
+	timer = eb.Timer()
+	assert.Equal(t, 1, clock.Timers())
+	assert.True(t, timer.Stop())
+	assert.Equal(t, 0, clock.Timers())
+	clock.Advance(time.Minute)
+	select {
+	case <-timer.C():
+		t.Error("stopped timer fired")
+	default:
+	}
+}
@@ -0,0 +1,268 @@
+package flagutils
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"net"
+	"net/url"
+	"reflect"
+	"time"
+)
+
+// Binding is the set of flags bound to the fields of a struct by Bind.
+type Binding struct {
+	fs     *flag.FlagSet
+	fields []boundField
+}
+
+type boundField struct {
+	field  string
+	name   string
+	usage  string
+	env    string
+	secret bool
+	value  flag.Value
+}
+
+// boundFlag is a Flag which writes the parsed value to the field of a struct.
+type boundFlag[T any] struct {
+	Flag[T]
+	ptr *T
+}
+
+func (b *boundFlag[T]) Set(s string) error {
+	if err := b.Flag.Set(s); err != nil {
+		return err
+	}
+	*b.ptr = b.Flag.Value
+	return nil
+}
+
+// boundSlice is a SliceFlag which writes the parsed values to the field of a struct.
+type boundSlice[T any] struct {
+	SliceFlag[T]
+	ptr *[]T
+}
+
+func (b *boundSlice[T]) Set(s string) error {
+	if err := b.SliceFlag.Set(s); err != nil {
+		return err
+	}
+	*b.ptr = b.SliceFlag.Value
+	return nil
+}
+
+// boundMap is a StringMapFlag which writes the parsed pairs to the field of a struct.
+type boundMap struct {
+	StringMapFlag
+	ptr *map[string]string
+}
+
+func (b *boundMap) Set(s string) error {
+	if err := b.StringMapFlag.Set(s); err != nil {
+		return err
+	}
+	*b.ptr = b.StringMapFlag.Value
+	return nil
+}
+
+func bindFlag[T any](ptr *T) flag.Value {
+	return &boundFlag[T]{Flag: Flag[T]{Value: *ptr}, ptr: ptr}
+}
+
+func bindSlice[T any](ptr *[]T) flag.Value {
+	return &boundSlice[T]{SliceFlag: SliceFlag[T]{Value: append([]T(nil), *ptr...), Replace: true}, ptr: ptr}
+}
+
+func bindMap(ptr *map[string]string) flag.Value {
+	f := &boundMap{StringMapFlag: StringMapFlag{Value: make(map[string]string), Replace: true}, ptr: ptr}
+	for k, v := range *ptr {
+		f.Value[k] = v
+	}
+	return f
+}
+
+// Bind registers in fs (flag.CommandLine if fs is nil) the flags for the fields of the struct
+// pointed by cfg which are tagged by `flag:"name" usage:"..." env:"..."`
+// (and `secret:"true"` for the values which must be masked by Effective).
+// The current values of the fields are the defaults, the parsed values are written to the fields.
+// The fields of types implementing flag.Value are registered as is, the fields of basic types,
+// slices of strings, ints and durations and maps of strings by the flags of this package,
+// the slices and maps are replaced on the first use of the flag.
+// The untagged struct fields are bound recursively.
+func Bind(fs *flag.FlagSet, cfg interface{}) (*Binding, error) {
+	v := reflect.ValueOf(cfg)
+	if v.Kind() != reflect.Ptr || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return nil, errors.New("flagutils: Bind expects a pointer to a struct")
+	}
+	if fs == nil {
+		fs = flag.CommandLine
+	}
+	b := &Binding{fs: fs}
+	if err := b.bind(v.Elem(), ""); err != nil {
+		return nil, err
+	}
+	for _, f := range b.fields {
+		fs.Var(f.value, f.name, f.usage)
+	}
+	return b, nil
+}
+
+func (b *Binding) bind(v reflect.Value, prefix string) error {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if sf.PkgPath != "" {
+			continue
+		}
+		fv := v.Field(i)
+		name := sf.Tag.Get("flag")
+		if name == "" && fv.Kind() == reflect.Struct {
+			nested := prefix
+			if !sf.Anonymous {
+				nested += sf.Name + "."
+			}
+			if err := b.bind(fv, nested); err != nil {
+				return err
+			}
+			continue
+		}
+		if name == "" || name == "-" {
+			continue
+		}
+		value, err := bindValue(fv)
+		if err != nil {
+			return fmt.Errorf("flagutils: field %s%s: %v", prefix, sf.Name, err)
+		}
+		b.fields = append(b.fields, boundField{
+			field:  prefix + sf.Name,
+			name:   name,
+			usage:  sf.Tag.Get("usage"),
+			env:    sf.Tag.Get("env"),
+			secret: sf.Tag.Get("secret") == "true",
+			value:  value,
+		})
+	}
+	return nil
+}
+
+func bindValue(v reflect.Value) (flag.Value, error) {
+	p := v.Addr().Interface()
+	if value, ok := p.(flag.Value); ok {
+		return value, nil
+	}
+	switch p := p.(type) {
+	case *bool:
+		return bindFlag(p), nil
+	case *int:
+		return bindFlag(p), nil
+	case *int8:
+		return bindFlag(p), nil
+	case *int16:
+		return bindFlag(p), nil
+	case *int32:
+		return bindFlag(p), nil
+	case *int64:
+		return bindFlag(p), nil
+	case *uint:
+		return bindFlag(p), nil
+	case *uint8:
+		return bindFlag(p), nil
+	case *uint16:
+		return bindFlag(p), nil
+	case *uint32:
+		return bindFlag(p), nil
+	case *uint64:
+		return bindFlag(p), nil
+	case *string:
+		return bindFlag(p), nil
+	case *float32:
+		return bindFlag(p), nil
+	case *float64:
+		return bindFlag(p), nil
+	case *time.Duration:
+		return bindFlag(p), nil
+	case *ByteSize:
+		return bindFlag(p), nil
+	case *time.Time:
+		return bindFlag(p), nil
+	case **url.URL:
+		return bindFlag(p), nil
+	case *net.IP:
+		return bindFlag(p), nil
+	case **net.IPNet:
+		return bindFlag(p), nil
+	case *HostPort:
+		return bindFlag(p), nil
+	case *ExistingPath:
+		return bindFlag(p), nil
+	case *[]string:
+		return bindSlice(p), nil
+	case *[]int:
+		return bindSlice(p), nil
+	case *[]time.Duration:
+		return bindSlice(p), nil
+	case *map[string]string:
+		return bindMap(p), nil
+	}
+	return nil, fmt.Errorf("unsupported type %v", v.Type())
+}
+
+// Resolve fills the bound flags which are not passed on the command line
+// like Resolver.Resolve, the env tags of the fields override the names of environment variables.
+func (b *Binding) Resolve(r *Resolver) error {
+	rr := *r
+	rr.EnvNames = make(map[string]string, len(r.EnvNames)+len(b.fields))
+	for name, env := range r.EnvNames {
+		rr.EnvNames[name] = env
+	}
+	for _, f := range b.fields {
+		if f.env != "" {
+			rr.EnvNames[f.name] = f.env
+		}
+	}
+	return rr.Resolve(b.fs)
+}
+
+// IsSet reports whether the field (e.g. "Addr" or "Server.Addr" for nested structs)
+// is set explicitly by the command line or by Resolve.
+func (b *Binding) IsSet(field string) bool {
+	return b.Source(field) != SourceDefault
+}
+
+// Source returns the source of the value of the field.
+func (b *Binding) Source(field string) Source {
+	for _, f := range b.fields {
+		if f.field == field {
+			return b.source(f)
+		}
+	}
+	return SourceDefault
+}
+
+// SetFields returns the names of the fields which are set explicitly.
+func (b *Binding) SetFields() []string {
+	var fields []string
+	for _, f := range b.fields {
+		if b.source(f) != SourceDefault {
+			fields = append(fields, f.field)
+		}
+	}
+	return fields
+}
+
+func (b *Binding) source(f boundField) Source {
+	return sourceOf(b.fs, b.fs.Lookup(f.name))
+}
+
+// Secrets returns the names of the flags of the fields tagged by `secret:"true"`.
+func (b *Binding) Secrets() []string {
+	var names []string
+	for _, f := range b.fields {
+		if f.secret {
+			names = append(names, f.name)
+		}
+	}
+	return names
+}
@@ -0,0 +1,94 @@
+package flagutils
+
+import (
+	"flag"
+	"io/ioutil"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type logConfig struct {
+	Level StringFlag `flag:"log-level" usage:"log level"`
+}
+
+type serverConfig struct {
+	Addr     string            `flag:"addr" usage:"listen address" env:"LISTEN_ADDR"`
+	Timeout  time.Duration     `flag:"timeout" usage:"read timeout"`
+	Debug    bool              `flag:"debug" usage:"debug mode"`
+	Workers  int               `flag:"workers"`
+	Peers    []string          `flag:"peer" usage:"peers"`
+	Labels   map[string]string `flag:"label" usage:"labels"`
+	Log      logConfig
+	Ignored  string `flag:"-"`
+	Untagged string
+	internal string
+}
+
+func TestBind(t *testing.T) {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.SetOutput(ioutil.Discard)
+	cfg := serverConfig{
+		Addr:    ":8822",
+		Timeout: 20 * time.Second,
+		Workers: 2,
+		Peers:   []string{"localhost"},
+	}
+	b, err := Bind(fs, &cfg)
+	assert.NoError(t, err)
+	assert.Equal(t, ":8822", fs.Lookup("addr").DefValue)
+	assert.Equal(t, "listen address", fs.Lookup("addr").Usage)
+	assert.Equal(t, "localhost", fs.Lookup("peer").DefValue)
+	assert.Nil(t, fs.Lookup("Ignored"))
+	assert.Nil(t, fs.Lookup("Untagged"))
+
+	err = fs.Parse([]string{"-debug", "-peer=a", "-peer", "b", "-label", "env=prod", "-log-level=info"})
+	assert.NoError(t, err)
+	env := map[string]string{"LISTEN_ADDR": ":9000", "SRV_WORKERS": "4", "SRV_ADDR": ":1"}
+	err = b.Resolve(&Resolver{
+		Prefix: "srv",
+		LookupEnv: func(key string) (string, bool) {
+			v, ok := env[key]
+			return v, ok
+		},
+	})
+	assert.NoError(t, err)
+
+	assert.Equal(t, ":9000", cfg.Addr)
+	assert.Equal(t, 20*time.Second, cfg.Timeout)
+	assert.True(t, cfg.Debug)
+	assert.Equal(t, 4, cfg.Workers)
+	assert.Equal(t, []string{"a", "b"}, cfg.Peers)
+	assert.Equal(t, map[string]string{"env": "prod"}, cfg.Labels)
+	assert.Equal(t, "info", cfg.Log.Level.Value)
+
+	assert.Equal(t, []string{"Addr", "Debug", "Workers", "Peers", "Labels", "Log.Level"}, b.SetFields())
+	assert.True(t, b.IsSet("Debug"))
+	assert.False(t, b.IsSet("Timeout"))
+	assert.Equal(t, SourceEnv, b.Source("Addr"))
+	assert.Equal(t, SourceFlag, b.Source("Log.Level"))
+	assert.Equal(t, SourceDefault, b.Source("Unknown"))
+}
+
+func TestBindWithoutResolve(t *testing.T) {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	var cfg serverConfig
+	b, err := Bind(fs, &cfg)
+	assert.NoError(t, err)
+	assert.NoError(t, fs.Parse([]string{"-workers=3"}))
+	assert.Equal(t, 3, cfg.Workers)
+	assert.Equal(t, []string{"Workers"}, b.SetFields())
+}
+
+func TestBindErrors(t *testing.T) {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	var cfg serverConfig
+	_, err := Bind(fs, cfg)
+	assert.Error(t, err)
+	var bad struct {
+		Ratio complex64 `flag:"ratio"`
+	}
+	_, err = Bind(fs, &bad)
+	assert.Error(t, err)
+}
@@ -0,0 +1,244 @@
+package flagutils
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+)
+
+// Command is a node of a command tree: "tool [flags] sub [flags] args".
+// Every command parses its own flags and dispatches the rest of arguments
+// to the child named by the first argument or to Run.
+type Command struct {
+	// Name is used to call the command, the name of the root is the name of the tool
+	Name string
+	// Short is the one-line description shown in the list of commands
+	Short string
+	// Long is the description shown in the help of the command
+	Long string
+	// ArgsUsage describes the arguments, e.g. "[source directories]"
+	ArgsUsage string
+	// Flags of the command, created on the first use of FlagSet if omitted
+	Flags *flag.FlagSet
+	// Run is called with the arguments left after parsing flags,
+	// the command without Run requires one of the children
+	Run func(cmd *Command, args []string) error
+	// Output receives usage and errors, the output of the parent or os.Stderr if omitted
+	Output io.Writer
+
+	parent   *Command
+	children []*Command
+}
+
+// ErrNoCommand is returned by Execute when a command without Run is called without a child.
+var ErrNoCommand = errors.New("flagutils: command is required")
+
+// AddCommand adds the children of the command.
+func (c *Command) AddCommand(children ...*Command) *Command {
+	for _, child := range children {
+		child.parent = c
+		c.children = append(c.children, child)
+	}
+	return c
+}
+
+// Commands returns the children of the command.
+func (c *Command) Commands() []*Command {
+	return c.children
+}
+
+// Parent returns the parent of the command or nil for the root.
+func (c *Command) Parent() *Command {
+	return c.parent
+}
+
+// Path returns the names of the command and its parents, e.g. "tool sub".
+func (c *Command) Path() string {
+	if c.parent == nil {
+		return c.Name
+	}
+	return c.parent.Path() + " " + c.Name
+}
+
+// FlagSet returns the flags of the command.
+func (c *Command) FlagSet() *flag.FlagSet {
+	if c.Flags == nil {
+		c.Flags = flag.NewFlagSet(c.Name, flag.ContinueOnError)
+	}
+	return c.Flags
+}
+
+func (c *Command) output() io.Writer {
+	for cmd := c; cmd != nil; cmd = cmd.parent {
+		if cmd.Output != nil {
+			return cmd.Output
+		}
+	}
+	return os.Stderr
+}
+
+// Execute parses args (without the name of the tool) and runs the command chosen by them.
+// It returns flag.ErrHelp if the help is requested by -h or -help
+// and *ParseError if a value of a flag is invalid.
+func (c *Command) Execute(args []string) error {
+	fs := c.FlagSet()
+	fs.SetOutput(c.output())
+	fs.Usage = c.PrintUsage
+	if err := Parse(fs, args); err != nil {
+		return err
+	}
+	args = fs.Args()
+	if len(args) > 0 {
+		if child := c.find(args[0]); child != nil {
+			return child.Execute(args[1:])
+		}
+	}
+	if c.Run == nil {
+		if len(args) > 0 {
+			fmt.Fprintf(c.output(), "unknown command %q for %q\n", args[0], c.Path())
+		}
+		c.PrintUsage()
+		return ErrNoCommand
+	}
+	return c.Run(c, args)
+}
+
+func (c *Command) find(name string) *Command {
+	for _, child := range c.children {
+		if child.Name == name {
+			return child
+		}
+	}
+	return nil
+}
+
+// PrintUsage prints the help of the command: the usage line, the description,
+// the list of children and the flags.
+func (c *Command) PrintUsage() {
+	w := c.output()
+	usage := c.Path()
+	if c.hasFlags() {
+		usage += " [flags]"
+	}
+	if len(c.children) > 0 {
+		if c.Run == nil {
+			usage += " <command>"
+		} else {
+			usage += " [command]"
+		}
+	}
+	if c.ArgsUsage != "" {
+		usage += " " + c.ArgsUsage
+	}
+	fmt.Fprintf(w, "Usage:\n\n  %s\n", usage)
+	if c.Long != "" {
+		fmt.Fprintf(w, "\n%s\n", c.Long)
+	} else if c.Short != "" {
+		fmt.Fprintf(w, "\n%s\n", c.Short)
+	}
+	if len(c.children) > 0 {
+		fmt.Fprintf(w, "\nCommands:\n\n")
+		width := 0
+		for _, child := range c.children {
+			if len(child.Name) > width {
+				width = len(child.Name)
+			}
+		}
+		for _, child := range c.children {
+			fmt.Fprintf(w, "  %-*s  %s\n", width, child.Name, child.Short)
+		}
+	}
+	if c.hasFlags() {
+		fmt.Fprintf(w, "\nFlags:\n\n")
+		fs := c.FlagSet()
+		fs.SetOutput(w)
+		fs.PrintDefaults()
+	}
+}
+
+func (c *Command) hasFlags() bool {
+	has := false
+	c.FlagSet().VisitAll(func(*flag.Flag) {
+		has = true
+	})
+	return has
+}
+
+// Completion writes the completion script of the command tree for the shell ("bash" or "zsh").
+func (c *Command) Completion(w io.Writer, shell string) error {
+	switch shell {
+	case "bash":
+		return c.bashCompletion(w)
+	case "zsh":
+		fmt.Fprintln(w, "autoload -U +X bashcompinit && bashcompinit")
+		return c.bashCompletion(w)
+	}
+	return fmt.Errorf("flagutils: unsupported shell %q", shell)
+}
+
+func (c *Command) bashCompletion(w io.Writer) error {
+	var (
+		paths []string
+		words = make(map[string][]string)
+	)
+	var walk func(cmd *Command)
+	walk = func(cmd *Command) {
+		path := cmd.Path()
+		if cmd.parent != nil {
+			paths = append(paths, path)
+		}
+		for _, child := range cmd.children {
+			words[path] = append(words[path], child.Name)
+		}
+		cmd.FlagSet().VisitAll(func(f *flag.Flag) {
+			words[path] = append(words[path], "-"+f.Name)
+		})
+		for _, child := range cmd.children {
+			walk(child)
+		}
+	}
+	walk(c)
+
+	fn := "_" + strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' {
+			return r
+		}
+		return '_'
+	}, c.Name)
+	cases := make([]string, 0, len(words))
+	for path := range words {
+		cases = append(cases, path)
+	}
+	sort.Strings(cases)
+
+	fmt.Fprintf(w, "%s() {\n", fn)
+	fmt.Fprintf(w, "    local cur=\"${COMP_WORDS[COMP_CWORD]}\" cmd=%q words i\n", c.Name)
+	fmt.Fprintf(w, "    for ((i = 1; i < COMP_CWORD; i++)); do\n")
+	fmt.Fprintf(w, "        case \"$cmd ${COMP_WORDS[i]}\" in\n")
+	if len(paths) > 0 {
+		fmt.Fprintf(w, "        %s) cmd=\"$cmd ${COMP_WORDS[i]}\" ;;\n", quoteAll(paths))
+	}
+	fmt.Fprintf(w, "        esac\n")
+	fmt.Fprintf(w, "    done\n")
+	fmt.Fprintf(w, "    case \"$cmd\" in\n")
+	for _, path := range cases {
+		fmt.Fprintf(w, "    %q) words=%q ;;\n", path, strings.Join(words[path], " "))
+	}
+	fmt.Fprintf(w, "    esac\n")
+	fmt.Fprintf(w, "    COMPREPLY=($(compgen -W \"$words\" -- \"$cur\"))\n")
+	fmt.Fprintf(w, "}\n")
+	_, err := fmt.Fprintf(w, "complete -F %s %s\n", fn, c.Name)
+	return err
+}
+
+func quoteAll(items []string) string {
+	quoted := make([]string, len(items))
+	for i, item := range items {
+		quoted[i] = fmt.Sprintf("%q", item)
+	}
+	return strings.Join(quoted, "|")
+}
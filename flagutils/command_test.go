@@ -0,0 +1,115 @@
+package flagutils
+
+import (
+	"bytes"
+	"flag"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type commandCall struct {
+	name string
+	args []string
+}
+
+func TestCommand(t *testing.T) {
+	var (
+		out   bytes.Buffer
+		calls []commandCall
+	)
+	record := func(cmd *Command, args []string) error {
+		calls = append(calls, commandCall{cmd.Path(), args})
+		return nil
+	}
+	root := &Command{Name: "tool", Short: "test tool", Output: &out}
+	verbose := Bool(root.FlagSet(), "v", false, "verbose output")
+	hash := &Command{Name: "hash", Short: "hash text", ArgsUsage: "text", Run: record}
+	algo := Enum(hash.FlagSet(), "a", "bcrypt", []string{"bcrypt", "md5"}, "hash algorithm")
+	gen := &Command{Name: "gen", Short: "generate code", Long: "Generate the code of ORM."}
+	gen.AddCommand(&Command{Name: "orm", Short: "generate ORM", Run: record})
+	root.AddCommand(hash, gen)
+
+	assert.NoError(t, root.Execute([]string{"-v", "hash", "-a", "md5", "some", "text"}))
+	assert.NoError(t, root.Execute([]string{"gen", "orm", "./model"}))
+	assert.Equal(t, []commandCall{
+		{"tool hash", []string{"some", "text"}},
+		{"tool gen orm", []string{"./model"}},
+	}, calls)
+	assert.True(t, verbose.Value)
+	assert.Equal(t, "md5", algo.Value)
+	assert.Equal(t, "", out.String())
+
+	assert.Equal(t, ErrNoCommand, root.Execute(nil))
+	assert.True(t, strings.HasPrefix(out.String(), "Usage:\n\n  tool [flags] <command>\n"))
+	out.Reset()
+	assert.Equal(t, ErrNoCommand, root.Execute([]string{"run"}))
+	assert.True(t, strings.HasPrefix(out.String(), `unknown command "run" for "tool"`))
+	out.Reset()
+	assert.Error(t, root.Execute([]string{"hash", "-a", "sha1"}))
+	assert.Contains(t, out.String(), "invalid value")
+
+	// help
+	calls = nil
+	out.Reset()
+	assert.Equal(t, flag.ErrHelp, root.Execute([]string{"-h"}))
+	assert.Equal(t, `Usage:
+
+  tool [flags] <command>
+
+test tool
+
+Commands:
+
+  hash  hash text
+  gen   generate code
+
+Flags:
+
+  -v	verbose output
+`, out.String())
+
+	out.Reset()
+	assert.Equal(t, flag.ErrHelp, root.Execute([]string{"gen", "-help"}))
+	assert.Equal(t, `Usage:
+
+  tool gen <command>
+
+Generate the code of ORM.
+
+Commands:
+
+  orm  generate ORM
+`, out.String())
+
+	out.Reset()
+	assert.Equal(t, flag.ErrHelp, root.Execute([]string{"hash", "-h"}))
+	assert.True(t, strings.HasPrefix(out.String(), "Usage:\n\n  tool hash [flags] text\n"))
+	assert.Empty(t, calls)
+
+	// completion
+	var script bytes.Buffer
+	assert.NoError(t, root.Completion(&script, "bash"))
+	assert.Equal(t, `_tool() {
+    local cur="${COMP_WORDS[COMP_CWORD]}" cmd="tool" words i
+    for ((i = 1; i < COMP_CWORD; i++)); do
+        case "$cmd ${COMP_WORDS[i]}" in
+        "tool hash"|"tool gen"|"tool gen orm") cmd="$cmd ${COMP_WORDS[i]}" ;;
+        esac
+    done
+    case "$cmd" in
+    "tool") words="hash gen -v" ;;
+    "tool gen") words="orm" ;;
+    "tool hash") words="-a" ;;
+    esac
+    COMPREPLY=($(compgen -W "$words" -- "$cur"))
+}
+complete -F _tool tool
+`, script.String())
+
+	script.Reset()
+	assert.NoError(t, root.Completion(&script, "zsh"))
+	assert.True(t, strings.HasPrefix(script.String(), "autoload -U +X bashcompinit && bashcompinit\n_tool() {"))
+	assert.Error(t, root.Completion(&script, "fish"))
+}
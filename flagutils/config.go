@@ -0,0 +1,199 @@
+package flagutils
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// readConfig reads the values of flags from the file,
+// the format is chosen by the extension: .json, .yaml/.yml or INI-style otherwise.
+func readConfig(path string) (map[string]string, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var values map[string]string
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		values, err = parseJSON(data)
+	case ".yaml", ".yml":
+		values, err = parseYAML(bytes.NewReader(data))
+	default:
+		values, err = parseINI(bytes.NewReader(data))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("flagutils: %s: %v", path, err)
+	}
+	return values, nil
+}
+
+// parseJSON parses a JSON object, arrays are converted into comma-separated lists
+// and objects into comma-separated lists of key=value pairs.
+func parseJSON(data []byte) (map[string]string, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var obj map[string]interface{}
+	if err := dec.Decode(&obj); err != nil {
+		return nil, err
+	}
+	values := make(map[string]string, len(obj))
+	for k, v := range obj {
+		values[k] = jsonString(v)
+	}
+	return values, nil
+}
+
+func jsonString(v interface{}) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case []interface{}:
+		items := make([]string, len(v))
+		for i, item := range v {
+			items[i] = jsonString(item)
+		}
+		return strings.Join(items, ",")
+	case map[string]interface{}:
+		items := make([]string, 0, len(v))
+		for k, item := range v {
+			items = append(items, k+"="+jsonString(item))
+		}
+		sort.Strings(items)
+		return strings.Join(items, ",")
+	}
+	return fmt.Sprintf("%v", v)
+}
+
+// parseYAML parses the subset of YAML used for flat configs:
+// "key: value" pairs, inline lists "[a, b]" and nested lists ("- a") or maps ("k: v")
+// which are converted into comma-separated lists like JSON ones.
+func parseYAML(r io.Reader) (map[string]string, error) {
+	values := make(map[string]string)
+	var (
+		key   string
+		items []string
+	)
+	flush := func() {
+		if key != "" {
+			values[key] = strings.Join(items, ",")
+		}
+		key, items = "", nil
+	}
+	scanner := bufio.NewScanner(r)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimRight(stripComment(scanner.Text(), "#"), " \t")
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || trimmed == "---" {
+			continue
+		}
+		if line[0] == ' ' || line[0] == '\t' {
+			if key == "" {
+				return nil, fmt.Errorf("line %d: unexpected indentation", n)
+			}
+			if strings.HasPrefix(trimmed, "- ") || trimmed == "-" {
+				items = append(items, unquote(strings.TrimSpace(trimmed[1:])))
+				continue
+			}
+			k, v, ok := splitPair(trimmed, ":")
+			if !ok {
+				return nil, fmt.Errorf("line %d: invalid item %q", n, trimmed)
+			}
+			items = append(items, k+"="+unquote(v))
+			continue
+		}
+		flush()
+		k, v, ok := splitPair(trimmed, ":")
+		if !ok {
+			return nil, fmt.Errorf("line %d: invalid pair %q", n, trimmed)
+		}
+		if v == "" {
+			key = k
+			continue
+		}
+		if strings.HasPrefix(v, "[") && strings.HasSuffix(v, "]") {
+			var list []string
+			for _, item := range strings.Split(v[1:len(v)-1], ",") {
+				if item = strings.TrimSpace(item); item != "" {
+					list = append(list, unquote(item))
+				}
+			}
+			v = strings.Join(list, ",")
+		} else {
+			v = unquote(v)
+		}
+		values[k] = v
+	}
+	flush()
+	return values, scanner.Err()
+}
+
+// parseINI parses "key = value" pairs, the keys of a [section] are prefixed by "section.".
+func parseINI(r io.Reader) (map[string]string, error) {
+	values := make(map[string]string)
+	section := ""
+	scanner := bufio.NewScanner(r)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || line[0] == ';' || line[0] == '#' {
+			continue
+		}
+		if line[0] == '[' {
+			if line[len(line)-1] != ']' {
+				return nil, fmt.Errorf("line %d: invalid section %q", n, line)
+			}
+			section = strings.TrimSpace(line[1 : len(line)-1])
+			continue
+		}
+		k, v, ok := splitPair(line, "=")
+		if !ok {
+			return nil, fmt.Errorf("line %d: invalid pair %q", n, line)
+		}
+		if section != "" {
+			k = section + "." + k
+		}
+		values[k] = unquote(v)
+	}
+	return values, scanner.Err()
+}
+
+func splitPair(s, sep string) (string, string, bool) {
+	i := strings.Index(s, sep)
+	if i <= 0 {
+		return "", "", false
+	}
+	return strings.TrimSpace(s[:i]), strings.TrimSpace(s[i+len(sep):]), true
+}
+
+// stripComment removes the comment which starts at the beginning of the line
+// or after a whitespace outside of quotes.
+func stripComment(line, mark string) string {
+	var quote byte
+	for i := 0; i < len(line); i++ {
+		c := line[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case strings.HasPrefix(line[i:], mark) && (i == 0 || line[i-1] == ' ' || line[i-1] == '\t'):
+			return line[:i]
+		}
+	}
+	return line
+}
+
+func unquote(s string) string {
+	if len(s) >= 2 && (s[0] == '"' || s[0] == '\'') && s[len(s)-1] == s[0] {
+		return s[1 : len(s)-1]
+	}
+	return s
+}
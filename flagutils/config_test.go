@@ -0,0 +1,76 @@
+package flagutils
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseJSON(t *testing.T) {
+	values, err := parseJSON([]byte(`{"addr": ":80", "n": 10, "f": 1.5, "on": false,
+		"peers": ["a", "b"], "labels": {"zone": "a", "env": "prod"}, "empty": null}`))
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"addr":   ":80",
+		"n":      "10",
+		"f":      "1.5",
+		"on":     "false",
+		"peers":  "a,b",
+		"labels": "env=prod,zone=a",
+		"empty":  "",
+	}, values)
+
+	_, err = parseJSON([]byte(`[1, 2]`))
+	assert.Error(t, err)
+}
+
+func TestParseYAML(t *testing.T) {
+	values, err := parseYAML(strings.NewReader(`---
+# chat server
+addr: ":80" # listen address
+token: 'a#b'
+peers: [a, "b"]
+hosts:
+  - x
+  - y
+labels:
+  env: prod
+  zone: a
+`))
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"addr":   ":80",
+		"token":  "a#b",
+		"peers":  "a,b",
+		"hosts":  "x,y",
+		"labels": "env=prod,zone=a",
+	}, values)
+
+	_, err = parseYAML(strings.NewReader("  - x\n"))
+	assert.Error(t, err)
+	_, err = parseYAML(strings.NewReader("addr\n"))
+	assert.Error(t, err)
+}
+
+func TestParseINI(t *testing.T) {
+	values, err := parseINI(strings.NewReader(`
+; chat server
+addr = :80
+token = "secret value"
+
+[log]
+level = debug
+`))
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"addr":      ":80",
+		"token":     "secret value",
+		"log.level": "debug",
+	}, values)
+
+	_, err = parseINI(strings.NewReader("[log\n"))
+	assert.Error(t, err)
+	_, err = parseINI(strings.NewReader("addr\n"))
+	assert.Error(t, err)
+}
@@ -0,0 +1,80 @@
+package flagutils
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"text/tabwriter"
+)
+
+// secretMask replaces the values of secret flags.
+const secretMask = "******"
+
+// FlagState is the effective value of a flag.
+type FlagState struct {
+	Name    string `json:"name"`
+	Value   string `json:"value"`
+	Default string `json:"default"`
+	Exist   bool   `json:"exist"`
+	Source  Source `json:"source"`
+	Secret  bool   `json:"secret,omitempty"`
+}
+
+// MarshalText returns the name of the source, so the source is readable in JSON.
+func (s Source) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// Effective returns the states of all flags of fs (flag.CommandLine if fs is nil)
+// sorted by names. The values of the secret flags are masked.
+func Effective(fs *flag.FlagSet, secrets ...string) []FlagState {
+	if fs == nil {
+		fs = flag.CommandLine
+	}
+	secret := make(map[string]bool, len(secrets))
+	for _, name := range secrets {
+		secret[name] = true
+	}
+	var states []FlagState
+	fs.VisitAll(func(f *flag.Flag) {
+		s := FlagState{
+			Name:    f.Name,
+			Value:   f.Value.String(),
+			Default: f.DefValue,
+			Source:  sourceOf(fs, f),
+			Secret:  secret[f.Name],
+		}
+		s.Exist = s.Source != SourceDefault
+		if s.Secret {
+			s.Value = mask(s.Value)
+			s.Default = mask(s.Default)
+		}
+		states = append(states, s)
+	})
+	return states
+}
+
+func mask(value string) string {
+	if value == "" {
+		return ""
+	}
+	return secretMask
+}
+
+// WriteTable writes the states as a table with the columns of name, value, default and source.
+func WriteTable(w io.Writer, states []FlagState) error {
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(tw, "NAME\tVALUE\tDEFAULT\tSOURCE")
+	for _, s := range states {
+		fmt.Fprintf(tw, "-%s\t%s\t%s\t%v\n", s.Name, s.Value, s.Default, s.Source)
+	}
+	return tw.Flush()
+}
+
+// WriteJSON writes the states as JSON array.
+func WriteJSON(w io.Writer, states []FlagState) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(states)
+}
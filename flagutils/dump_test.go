@@ -0,0 +1,63 @@
+package flagutils
+
+import (
+	"bytes"
+	"flag"
+	"io/ioutil"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEffective(t *testing.T) {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.SetOutput(ioutil.Discard)
+	var cfg struct {
+		Token   string        `flag:"t" usage:"telegram token" secret:"true"`
+		Timeout time.Duration `flag:"timeout" usage:"timeout"`
+	}
+	cfg.Timeout = time.Second
+	b, err := Bind(fs, &cfg)
+	assert.NoError(t, err)
+	Int(fs, "workers", 2, "workers")
+	var plain string
+	fs.StringVar(&plain, "plain", "", "plain flag")
+
+	assert.NoError(t, fs.Parse([]string{"-timeout=5s", "-plain=x"}))
+	r := Resolver{Prefix: "tgbot", LookupEnv: func(key string) (string, bool) {
+		return "123:abc", key == "TGBOT_T"
+	}}
+	assert.NoError(t, b.Resolve(&r))
+
+	states := Effective(fs, b.Secrets()...)
+	assert.Equal(t, []FlagState{
+		{Name: "plain", Value: "x", Default: "", Exist: true, Source: SourceFlag},
+		{Name: "t", Value: "******", Default: "", Exist: true, Source: SourceEnv, Secret: true},
+		{Name: "timeout", Value: "5s", Default: "1s", Exist: true, Source: SourceFlag},
+		{Name: "workers", Value: "2", Default: "2", Exist: false, Source: SourceDefault},
+	}, states)
+
+	var out bytes.Buffer
+	assert.NoError(t, WriteTable(&out, states))
+	assert.Equal(t, `NAME      VALUE   DEFAULT  SOURCE
+-plain    x                flag
+-t        ******           env
+-timeout  5s      1s       flag
+-workers  2       2        default
+`, out.String())
+
+	out.Reset()
+	assert.NoError(t, WriteJSON(&out, states[1:2]))
+	assert.Equal(t, `[
+  {
+    "name": "t",
+    "value": "******",
+    "default": "",
+    "exist": true,
+    "source": "env",
+    "secret": true
+  }
+]
+`, out.String())
+}
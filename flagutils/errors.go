@@ -0,0 +1,132 @@
+package flagutils
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"strconv"
+)
+
+// ParseError is returned by the flags of this package when the input can not be parsed.
+type ParseError struct {
+	// Flag is the name of the flag, it is empty when the value is set directly
+	// or by flag.FlagSet.Parse which reports the name itself, use Parse to fill it
+	Flag string
+	// Input is the raw text of the value
+	Input string
+	// Err is the cause, e.g. strconv.ErrRange or strconv.ErrSyntax
+	Err error
+}
+
+func (e *ParseError) Error() string {
+	if e.Flag == "" {
+		return e.Err.Error()
+	}
+	return fmt.Sprintf("invalid value %q for flag -%s: %v", e.Input, e.Flag, e.Err)
+}
+
+// Unwrap returns the cause.
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// newParseError returns ParseError of the input for the value of the type of zero,
+// the errors of strconv are reduced to their causes and the range errors name the type
+// (if zero is not nil).
+func newParseError(input string, zero interface{}, err error) *ParseError {
+	var perr *ParseError
+	if errors.As(err, &perr) {
+		return perr
+	}
+	var nerr *strconv.NumError
+	if errors.As(err, &nerr) {
+		err = nerr.Err
+	}
+	if zero != nil && errors.Is(err, strconv.ErrRange) {
+		err = fmt.Errorf("%w of %T", err, zero)
+	}
+	return &ParseError{Input: input, Err: err}
+}
+
+// Parse parses args like fs.Parse (fs is flag.CommandLine if nil), but the invalid value
+// of any flag is returned as *ParseError with the name of the flag, the raw input and the cause.
+// The values of the flags are wrapped during parsing, so fs must not be used concurrently.
+func Parse(fs *flag.FlagSet, args []string) error {
+	if fs == nil {
+		fs = flag.CommandLine
+	}
+	var (
+		perr     *ParseError
+		values   = make(map[*flag.Flag]flag.Value)
+		restored bool
+	)
+	restore := func() {
+		if restored {
+			return
+		}
+		restored = true
+		for f, v := range values {
+			f.Value = v
+		}
+	}
+	defer restore()
+	fs.VisitAll(func(f *flag.Flag) {
+		values[f] = f.Value
+		r := &recorder{Value: f.Value, name: f.Name, err: &perr}
+		if b, ok := f.Value.(interface{ IsBoolFlag() bool }); ok && b.IsBoolFlag() {
+			f.Value = boolRecorder{r}
+		} else {
+			f.Value = r
+		}
+	})
+	// the usage prints the defaults by the original values
+	usage := fs.Usage
+	fs.Usage = func() {
+		restore()
+		if usage != nil {
+			usage()
+			return
+		}
+		if fs.Name() == "" {
+			fmt.Fprintf(fs.Output(), "Usage:\n")
+		} else {
+			fmt.Fprintf(fs.Output(), "Usage of %s:\n", fs.Name())
+		}
+		fs.PrintDefaults()
+	}
+	defer func() { fs.Usage = usage }()
+
+	if err := fs.Parse(args); err != nil {
+		if perr != nil {
+			return perr
+		}
+		return err
+	}
+	return nil
+}
+
+// recorder keeps the first error of setting the value as ParseError with the name of the flag.
+type recorder struct {
+	flag.Value
+	name string
+	err  **ParseError
+}
+
+func (r *recorder) Set(s string) error {
+	err := r.Value.Set(s)
+	if err != nil && *r.err == nil {
+		perr := *newParseError(s, nil, err)
+		perr.Flag = r.name
+		*r.err = &perr
+	}
+	return err
+}
+
+// boolRecorder is the recorder of the flags which may be passed without a value.
+type boolRecorder struct {
+	*recorder
+}
+
+func (boolRecorder) IsBoolFlag() bool {
+	return true
+}
@@ -0,0 +1,135 @@
+package flagutils
+
+import (
+	"errors"
+	"flag"
+	"io/ioutil"
+	"math"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBitSizeFlags(t *testing.T) {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.SetOutput(ioutil.Discard)
+	i8 := Int8(fs, "i8", 0, "int8")
+	i16 := Int16(fs, "i16", 0, "int16")
+	i32 := Int32(fs, "i32", 0, "int32")
+	u8 := Uint8(fs, "u8", 0, "uint8")
+	u16 := Uint16(fs, "u16", 0, "uint16")
+	u32 := Uint32(fs, "u32", 0, "uint32")
+	f32 := Float32(fs, "f32", 0, "float32")
+	f64 := Float64(fs, "f64", 0, "float64")
+
+	err := fs.Parse([]string{
+		"-i8=-128", "-i16=0x7fff", "-i32=-2147483648",
+		"-u8=255", "-u16=65535", "-u32=4294967295",
+		"-f32=1.5", "-f64=1e308",
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, int8(math.MinInt8), i8.Value)
+	assert.Equal(t, int16(math.MaxInt16), i16.Value)
+	assert.Equal(t, int32(math.MinInt32), i32.Value)
+	assert.Equal(t, uint8(math.MaxUint8), u8.Value)
+	assert.Equal(t, uint16(math.MaxUint16), u16.Value)
+	assert.Equal(t, uint32(math.MaxUint32), u32.Value)
+	assert.Equal(t, float32(1.5), f32.Value)
+	assert.Equal(t, 1e308, f64.Value)
+
+	cases := []struct {
+		arg     string
+		message string
+	}{
+		{"-i8=128", `invalid value "128" for flag -i8: value out of range of int8`},
+		{"-i16=-32769", `invalid value "-32769" for flag -i16: value out of range of int16`},
+		{"-i32=2147483648", `invalid value "2147483648" for flag -i32: value out of range of int32`},
+		{"-u8=256", `invalid value "256" for flag -u8: value out of range of uint8`},
+		{"-u16=-1", `invalid value "-1" for flag -u16: invalid syntax`},
+		{"-u32=4294967296", `invalid value "4294967296" for flag -u32: value out of range of uint32`},
+		{"-f32=1e39", `invalid value "1e39" for flag -f32: value out of range of float32`},
+		{"-f64=1e309", `invalid value "1e309" for flag -f64: value out of range of float64`},
+	}
+	for _, c := range cases {
+		err := Parse(fs, []string{c.arg})
+		var perr *ParseError
+		if assert.True(t, errors.As(err, &perr), c.arg) {
+			assert.Equal(t, c.arg[1:strings.Index(c.arg, "=")], perr.Flag, c.arg)
+			assert.Equal(t, c.arg[strings.Index(c.arg, "=")+1:], perr.Input, c.arg)
+			assert.Equal(t, c.message, perr.Error(), c.arg)
+		}
+	}
+	assert.Equal(t, int8(math.MinInt8), i8.Value)
+}
+
+func TestParse(t *testing.T) {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.SetOutput(ioutil.Discard)
+	lvl := Int8(fs, "lvl", 1, "level")
+	verbose := fs.Bool("v", false, "verbose")
+	var plain int
+	fs.IntVar(&plain, "plain", 0, "flag of the flag package")
+
+	assert.NoError(t, Parse(fs, []string{"-v", "-lvl=5", "-plain=7", "arg"}))
+	assert.Equal(t, int8(5), lvl.Value)
+	assert.True(t, *verbose)
+	assert.Equal(t, 7, plain)
+	assert.Equal(t, []string{"arg"}, fs.Args())
+	// the original values are restored after parsing
+	assert.Equal(t, lvl, fs.Lookup("lvl").Value)
+
+	var perr *ParseError
+	err := Parse(fs, []string{"-lvl=300"})
+	assert.True(t, errors.As(err, &perr))
+	assert.Equal(t, "lvl", perr.Flag)
+	assert.Equal(t, "300", perr.Input)
+	assert.True(t, errors.Is(err, strconv.ErrRange))
+	assert.Equal(t, lvl, fs.Lookup("lvl").Value)
+
+	err = Parse(fs, []string{"-plain=x"})
+	assert.True(t, errors.As(err, &perr))
+	assert.Equal(t, "plain", perr.Flag)
+	assert.Equal(t, "x", perr.Input)
+
+	err = Parse(fs, []string{"-v=maybe"})
+	assert.True(t, errors.As(err, &perr))
+	assert.Equal(t, "v", perr.Flag)
+
+	// the errors which are not about values are returned as is
+	err = Parse(fs, []string{"-unknown"})
+	assert.Error(t, err)
+	assert.False(t, errors.As(err, &perr))
+}
+
+func TestParseError(t *testing.T) {
+	var i8 Int8Flag
+	err := i8.Set("1000")
+	var perr *ParseError
+	assert.True(t, errors.As(err, &perr))
+	assert.Equal(t, &ParseError{Input: "1000", Err: perr.Err}, perr)
+	assert.True(t, errors.Is(err, strconv.ErrRange))
+	assert.Equal(t, SourceDefault, i8.Source)
+
+	var ports IntSliceFlag
+	err = ports.Set("80,x")
+	assert.True(t, errors.As(err, &perr))
+	assert.Equal(t, "80,x", perr.Input)
+	assert.True(t, errors.Is(err, strconv.ErrSyntax))
+
+	var labels StringMapFlag
+	err = labels.Set("k")
+	assert.True(t, errors.As(err, &perr))
+
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	Uint8(fs, "level", 1, "level")
+	r := Resolver{LookupEnv: func(key string) (string, bool) {
+		return "300", key == "LEVEL"
+	}}
+	err = r.Resolve(fs)
+	assert.True(t, errors.As(err, &perr))
+	assert.Equal(t, "level", perr.Flag)
+	assert.Equal(t, "300", perr.Input)
+	assert.Equal(t, `invalid value "300" for flag -level: value out of range of uint8`, err.Error())
+}
@@ -11,175 +11,294 @@
 		flag.Var(&debug, "debug", "debug mode")
 		flag.Parse()
 
-		if debug.Exist {
+		if debug.Source == util.SourceDefault {
 			fmt.Printf("debug mode not set")
 		} else {
 			fmt.Printf("debug mode set to %v", debug.Value)
 		}
+
+	The flags of any type can be declared by Flag with a parser:
+		level := util.Var(flag.CommandLine, "level", logLevel(0), parseLevel, "log level")
+		port := util.Int(flag.CommandLine, "port", 8080, "listen port")
 */
 package flagutils
 
 import (
+	"flag"
 	"fmt"
+	"net"
+	"net/url"
 	"strconv"
 	"time"
 )
 
+// Parser converts the text of a flag into the value of type T
+type Parser[T any] func(s string) (T, error)
+
+// Flag represents a flag of type T and implements flag.Value interface.
+// The zero Flag uses the parser of the basic types (bool, ints, uints, floats,
+// string and time.Duration) and the types of this package (ByteSize, time.Time, *url.URL,
+// net.IP, *net.IPNet, HostPort and ExistingPath), flags of other types must be created by NewFlag.
+type Flag[T any] struct {
+	// Source is SourceFlag when the flag is set, Resolver records the other sources
+	Source Source
+	Value  T
+
+	parse Parser[T]
+}
+
 // BoolFlag represents bool flag and implements flag.boolFlag interface
-type BoolFlag struct {
-	Exist bool
-	Value bool
+type BoolFlag = Flag[bool]
+
+// IntFlag represents int flag and implements flag.Value interface
+type IntFlag = Flag[int]
+
+// Int8Flag represents int8 flag and implements flag.Value interface
+type Int8Flag = Flag[int8]
+
+// Int16Flag represents int16 flag and implements flag.Value interface
+type Int16Flag = Flag[int16]
+
+// Int32Flag represents int32 flag and implements flag.Value interface
+type Int32Flag = Flag[int32]
+
+// Int64Flag represents int64 flag and implements flag.Value interface
+type Int64Flag = Flag[int64]
+
+// UintFlag represents uint flag and implements flag.Value interface
+type UintFlag = Flag[uint]
+
+// Uint8Flag represents uint8 flag and implements flag.Value interface
+type Uint8Flag = Flag[uint8]
+
+// Uint16Flag represents uint16 flag and implements flag.Value interface
+type Uint16Flag = Flag[uint16]
+
+// Uint32Flag represents uint32 flag and implements flag.Value interface
+type Uint32Flag = Flag[uint32]
+
+// Uint64Flag represents uint64 flag and implements flag.Value interface
+type Uint64Flag = Flag[uint64]
+
+// StringFlag represents string flag and implements flag.Value interface
+type StringFlag = Flag[string]
+
+// Float32Flag represents float32 flag and implements flag.Value interface
+type Float32Flag = Flag[float32]
+
+// Float64Flag represents float64 flag and implements flag.Value interface
+type Float64Flag = Flag[float64]
+
+// DurationFlag represents duration flag and implements flag.Value interface
+type DurationFlag = Flag[time.Duration]
+
+// NewFlag returns a flag with the default value parsed by parse,
+// the parser of the basic type is used if parse is nil.
+func NewFlag[T any](value T, parse Parser[T]) *Flag[T] {
+	return &Flag[T]{Value: value, parse: parse}
 }
 
-func (b *BoolFlag) IsBoolFlag() bool {
-	return true
+// IsBoolFlag reports whether the flag may be passed without a value.
+func (f *Flag[T]) IsBoolFlag() bool {
+	_, ok := any(f.Value).(bool)
+	return ok
 }
 
-func (b *BoolFlag) Set(s string) error {
-	var err error
-	if s != "" {
-		b.Value, err = strconv.ParseBool(s)
+// Set parses s and marks the flag as passed on the command line.
+// An empty s keeps the current value unless it is a string.
+func (f *Flag[T]) Set(s string) error {
+	if _, ok := any(f.Value).(string); s != "" || ok {
+		parse := f.parse
+		if parse == nil {
+			parse = basicParser[T]()
+		}
+		if parse == nil {
+			return &ParseError{Input: s, Err: fmt.Errorf("no parser for %T", f.Value)}
+		}
+		value, err := parse(s)
+		if err != nil {
+			return newParseError(s, f.Value, err)
+		}
+		f.Value = value
 	}
-	b.Exist = true
-	return err
+	f.Source = SourceFlag
+	return nil
 }
 
-func (b *BoolFlag) String() string {
-	return fmt.Sprintf("%v", b.Value)
+func (f *Flag[T]) String() string {
+	if f == nil {
+		return ""
+	}
+	return formatValue(f.Value)
 }
 
-// IntFlag represents bool flag and implements flag.Value interface
-type IntFlag struct {
-	Exist bool
-	Value int
+// Get returns the value of the flag and implements flag.Getter interface.
+func (f *Flag[T]) Get() interface{} {
+	return f.Value
 }
 
-func (b *IntFlag) Set(s string) error {
-	if s != "" {
-		if value, err := strconv.ParseInt(s, 0, 64); err == nil {
-			b.Value = int(value)
-		} else {
-			return err
-		}
+// basicParser returns the parser of the basic type T or nil.
+func basicParser[T any]() Parser[T] {
+	var parse interface{}
+	var zero T
+	switch any(zero).(type) {
+	case bool:
+		parse = Parser[bool](strconv.ParseBool)
+	case int:
+		parse = intParser[int](strconv.IntSize)
+	case int8:
+		parse = intParser[int8](8)
+	case int16:
+		parse = intParser[int16](16)
+	case int32:
+		parse = intParser[int32](32)
+	case int64:
+		parse = intParser[int64](64)
+	case uint:
+		parse = uintParser[uint](strconv.IntSize)
+	case uint8:
+		parse = uintParser[uint8](8)
+	case uint16:
+		parse = uintParser[uint16](16)
+	case uint32:
+		parse = uintParser[uint32](32)
+	case uint64:
+		parse = uintParser[uint64](64)
+	case string:
+		parse = Parser[string](func(s string) (string, error) {
+			return s, nil
+		})
+	case float32:
+		parse = floatParser[float32](32)
+	case float64:
+		parse = floatParser[float64](64)
+	case time.Duration:
+		parse = Parser[time.Duration](time.ParseDuration)
+	case ByteSize:
+		parse = Parser[ByteSize](ParseByteSize)
+	case time.Time:
+		parse = Parser[time.Time](parseTime)
+	case *url.URL:
+		parse = Parser[*url.URL](parseURL)
+	case net.IP:
+		parse = Parser[net.IP](parseIP)
+	case *net.IPNet:
+		parse = Parser[*net.IPNet](parseCIDR)
+	case HostPort:
+		parse = Parser[HostPort](ParseHostPort)
+	case ExistingPath:
+		parse = Parser[ExistingPath](ParseExistingPath)
 	}
-	b.Exist = true
-	return nil
+	p, _ := parse.(Parser[T])
+	return p
 }
 
-func (b *IntFlag) String() string {
-	return fmt.Sprintf("%v", b.Value)
+// intParser returns the parser of signed integers which fit into bits.
+func intParser[T int | int8 | int16 | int32 | int64](bits int) Parser[T] {
+	return func(s string) (T, error) {
+		value, err := strconv.ParseInt(s, 0, bits)
+		return T(value), err
+	}
 }
 
-// Int64Flag represents bool flag and implements flag.Value interface
-type Int64Flag struct {
-	Exist bool
-	Value int64
+// uintParser returns the parser of unsigned integers which fit into bits.
+func uintParser[T uint | uint8 | uint16 | uint32 | uint64](bits int) Parser[T] {
+	return func(s string) (T, error) {
+		value, err := strconv.ParseUint(s, 0, bits)
+		return T(value), err
+	}
 }
 
-func (b *Int64Flag) Set(s string) error {
-	var err error
-	if s != "" {
-		b.Value, err = strconv.ParseInt(s, 0, 64)
+// floatParser returns the parser of floats of the precision bits.
+func floatParser[T float32 | float64](bits int) Parser[T] {
+	return func(s string) (T, error) {
+		value, err := strconv.ParseFloat(s, bits)
+		return T(value), err
 	}
-	b.Exist = true
-	return err
 }
 
-func (b *Int64Flag) String() string {
-	return fmt.Sprintf("%v", b.Value)
+// Var defines a flag of type T with the specified name, default value, parser and usage
+// in fs (flag.CommandLine if fs is nil) and returns the flag.
+func Var[T any](fs *flag.FlagSet, name string, value T, parse Parser[T], usage string) *Flag[T] {
+	f := NewFlag(value, parse)
+	if fs == nil {
+		fs = flag.CommandLine
+	}
+	fs.Var(f, name, usage)
+	return f
 }
 
-// UintFlag represents bool flag and implements flag.Value interface
-type UintFlag struct {
-	Exist bool
-	Value uint
+// Bool defines a bool flag in fs and returns the flag.
+func Bool(fs *flag.FlagSet, name string, value bool, usage string) *BoolFlag {
+	return Var(fs, name, value, nil, usage)
 }
 
-func (b *UintFlag) Set(s string) error {
-	if s != "" {
-		if value, err := strconv.ParseUint(s, 0, 64); err == nil {
-			b.Value = uint(value)
-		} else {
-			return err
-		}
-	}
-	b.Exist = true
-	return nil
+// Int defines an int flag in fs and returns the flag.
+func Int(fs *flag.FlagSet, name string, value int, usage string) *IntFlag {
+	return Var(fs, name, value, nil, usage)
 }
 
-func (b *UintFlag) String() string {
-	return fmt.Sprintf("%v", b.Value)
+// Int8 defines an int8 flag in fs and returns the flag.
+func Int8(fs *flag.FlagSet, name string, value int8, usage string) *Int8Flag {
+	return Var(fs, name, value, nil, usage)
 }
 
-// Uint64Flag represents bool flag and implements flag.Value interface
-type Uint64Flag struct {
-	Exist bool
-	Value uint64
+// Int16 defines an int16 flag in fs and returns the flag.
+func Int16(fs *flag.FlagSet, name string, value int16, usage string) *Int16Flag {
+	return Var(fs, name, value, nil, usage)
 }
 
-func (b *Uint64Flag) Set(s string) error {
-	var err error
-	if s != "" {
-		b.Value, err = strconv.ParseUint(s, 0, 64)
-	}
-	b.Exist = true
-	return err
+// Int32 defines an int32 flag in fs and returns the flag.
+func Int32(fs *flag.FlagSet, name string, value int32, usage string) *Int32Flag {
+	return Var(fs, name, value, nil, usage)
 }
 
-func (b *Uint64Flag) String() string {
-	return fmt.Sprintf("%v", b.Value)
+// Int64 defines an int64 flag in fs and returns the flag.
+func Int64(fs *flag.FlagSet, name string, value int64, usage string) *Int64Flag {
+	return Var(fs, name, value, nil, usage)
 }
 
-// StringFlag represents bool flag and implements flag.Value interface
-type StringFlag struct {
-	Exist bool
-	Value string
+// Uint defines an uint flag in fs and returns the flag.
+func Uint(fs *flag.FlagSet, name string, value uint, usage string) *UintFlag {
+	return Var(fs, name, value, nil, usage)
 }
 
-func (b *StringFlag) Set(s string) error {
-	b.Value = s
-	b.Exist = true
-	return nil
+// Uint8 defines an uint8 flag in fs and returns the flag.
+func Uint8(fs *flag.FlagSet, name string, value uint8, usage string) *Uint8Flag {
+	return Var(fs, name, value, nil, usage)
 }
 
-func (b *StringFlag) String() string {
-	return fmt.Sprintf("%v", b.Value)
+// Uint16 defines an uint16 flag in fs and returns the flag.
+func Uint16(fs *flag.FlagSet, name string, value uint16, usage string) *Uint16Flag {
+	return Var(fs, name, value, nil, usage)
 }
 
-// Float64Flag represents bool flag and implements flag.Value interface
-type Float64Flag struct {
-	Exist bool
-	Value float64
+// Uint32 defines an uint32 flag in fs and returns the flag.
+func Uint32(fs *flag.FlagSet, name string, value uint32, usage string) *Uint32Flag {
+	return Var(fs, name, value, nil, usage)
 }
 
-func (b *Float64Flag) Set(s string) error {
-	var err error
-	if s != "" {
-		b.Value, err = strconv.ParseFloat(s, 0)
-	}
-	b.Exist = true
-	return err
+// Uint64 defines an uint64 flag in fs and returns the flag.
+func Uint64(fs *flag.FlagSet, name string, value uint64, usage string) *Uint64Flag {
+	return Var(fs, name, value, nil, usage)
 }
 
-func (b *Float64Flag) String() string {
-	return fmt.Sprintf("%v", b.Value)
+// String defines a string flag in fs and returns the flag.
+func String(fs *flag.FlagSet, name string, value string, usage string) *StringFlag {
+	return Var(fs, name, value, nil, usage)
 }
 
-// DurationFlag represents bool flag and implements flag.Value interface
-type DurationFlag struct {
-	Exist bool
-	Value time.Duration
+// Float32 defines a float32 flag in fs and returns the flag.
+func Float32(fs *flag.FlagSet, name string, value float32, usage string) *Float32Flag {
+	return Var(fs, name, value, nil, usage)
 }
 
-func (b *DurationFlag) Set(s string) error {
-	var err error
-	if s != "" {
-		b.Value, err = time.ParseDuration(s)
-	}
-	b.Exist = true
-	return err
+// Float64 defines a float64 flag in fs and returns the flag.
+func Float64(fs *flag.FlagSet, name string, value float64, usage string) *Float64Flag {
+	return Var(fs, name, value, nil, usage)
 }
 
-func (b *DurationFlag) String() string {
-	return fmt.Sprintf("%v", b.Value)
+// Duration defines a duration flag in fs and returns the flag.
+func Duration(fs *flag.FlagSet, name string, value time.Duration, usage string) *DurationFlag {
+	return Var(fs, name, value, nil, usage)
 }
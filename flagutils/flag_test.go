@@ -1,9 +1,12 @@
 package flagutils
 
 import (
+	"errors"
 	"flag"
+	"io/ioutil"
 	"sync"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -45,35 +48,35 @@ func TestNotExistFlags(t *testing.T) {
 	cases := []useCase{
 		{
 			flagName:  "test_bool_flag",
-			flagValue: &BoolFlag{Exist: false, Value: false},
+			flagValue: &BoolFlag{Value: false},
 		},
 		{
 			flagName:  "test_int_flag",
-			flagValue: &IntFlag{Exist: false, Value: 0},
+			flagValue: &IntFlag{Value: 0},
 		},
 		{
 			flagName:  "test_int64_flag",
-			flagValue: &Int64Flag{Exist: false, Value: 0},
+			flagValue: &Int64Flag{Value: 0},
 		},
 		{
 			flagName:  "test_uint_flag",
-			flagValue: &UintFlag{Exist: false, Value: 0},
+			flagValue: &UintFlag{Value: 0},
 		},
 		{
 			flagName:  "test_uint64_flag",
-			flagValue: &Uint64Flag{Exist: false, Value: 0},
+			flagValue: &Uint64Flag{Value: 0},
 		},
 		{
 			flagName:  "test_string_flag",
-			flagValue: &StringFlag{Exist: false, Value: ""},
+			flagValue: &StringFlag{Value: ""},
 		},
 		{
 			flagName:  "test_float64_flag",
-			flagValue: &Float64Flag{Exist: false, Value: 0},
+			flagValue: &Float64Flag{Value: 0},
 		},
 		{
 			flagName:  "test_duration_flag",
-			flagValue: &DurationFlag{Exist: false, Value: 0},
+			flagValue: &DurationFlag{Value: 0},
 		},
 	}
 	testCases(t, args, cases)
@@ -93,35 +96,35 @@ func TestExistEmptyFlags(t *testing.T) {
 	cases := []useCase{
 		{
 			flagName:  "test_bool_flag",
-			flagValue: &BoolFlag{Exist: true, Value: true},
+			flagValue: &BoolFlag{Source: SourceFlag, Value: true},
 		},
 		{
 			flagName:  "test_int_flag",
-			flagValue: &IntFlag{Exist: true, Value: 0},
+			flagValue: &IntFlag{Source: SourceFlag, Value: 0},
 		},
 		{
 			flagName:  "test_int64_flag",
-			flagValue: &Int64Flag{Exist: true, Value: 0},
+			flagValue: &Int64Flag{Source: SourceFlag, Value: 0},
 		},
 		{
 			flagName:  "test_uint_flag",
-			flagValue: &UintFlag{Exist: true, Value: 0},
+			flagValue: &UintFlag{Source: SourceFlag, Value: 0},
 		},
 		{
 			flagName:  "test_uint64_flag",
-			flagValue: &Uint64Flag{Exist: true, Value: 0},
+			flagValue: &Uint64Flag{Source: SourceFlag, Value: 0},
 		},
 		{
 			flagName:  "test_string_flag",
-			flagValue: &StringFlag{Exist: true, Value: ""},
+			flagValue: &StringFlag{Source: SourceFlag, Value: ""},
 		},
 		{
 			flagName:  "test_float64_flag",
-			flagValue: &Float64Flag{Exist: true, Value: 0},
+			flagValue: &Float64Flag{Source: SourceFlag, Value: 0},
 		},
 		{
 			flagName:  "test_duration_flag",
-			flagValue: &DurationFlag{Exist: true, Value: 0},
+			flagValue: &DurationFlag{Source: SourceFlag, Value: 0},
 		},
 	}
 	testCases(t, args, cases)
@@ -141,35 +144,35 @@ func TestExistNotEmptyFlags(t *testing.T) {
 	cases := []useCase{
 		{
 			flagName:  "test_bool_flag",
-			flagValue: &BoolFlag{Exist: true, Value: false},
+			flagValue: &BoolFlag{Source: SourceFlag, Value: false},
 		},
 		{
 			flagName:  "test_int_flag",
-			flagValue: &IntFlag{Exist: true, Value: 1},
+			flagValue: &IntFlag{Source: SourceFlag, Value: 1},
 		},
 		{
 			flagName:  "test_int64_flag",
-			flagValue: &Int64Flag{Exist: true, Value: 2},
+			flagValue: &Int64Flag{Source: SourceFlag, Value: 2},
 		},
 		{
 			flagName:  "test_uint_flag",
-			flagValue: &UintFlag{Exist: true, Value: 3},
+			flagValue: &UintFlag{Source: SourceFlag, Value: 3},
 		},
 		{
 			flagName:  "test_uint64_flag",
-			flagValue: &Uint64Flag{Exist: true, Value: 4},
+			flagValue: &Uint64Flag{Source: SourceFlag, Value: 4},
 		},
 		{
 			flagName:  "test_string_flag",
-			flagValue: &StringFlag{Exist: true, Value: "/bin/bash"},
+			flagValue: &StringFlag{Source: SourceFlag, Value: "/bin/bash"},
 		},
 		{
 			flagName:  "test_float64_flag",
-			flagValue: &Float64Flag{Exist: true, Value: 1.23},
+			flagValue: &Float64Flag{Source: SourceFlag, Value: 1.23},
 		},
 		{
 			flagName:  "test_duration_flag",
-			flagValue: &DurationFlag{Exist: true, Value: 5000000},
+			flagValue: &DurationFlag{Source: SourceFlag, Value: 5000000},
 		},
 	}
 	testCases(t, args, cases)
@@ -194,3 +197,48 @@ func testCases(t *testing.T, args []string, cases []useCase) {
 		assert.Equal(t, c.flagValue, m[c.flagName], c.flagName)
 	}
 }
+
+type level int
+
+func parseLevel(s string) (level, error) {
+	switch s {
+	case "debug":
+		return 0, nil
+	case "info":
+		return 1, nil
+	case "error":
+		return 2, nil
+	}
+	return 0, errors.New("unknown level " + s)
+}
+
+func TestVarFlags(t *testing.T) {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.SetOutput(ioutil.Discard)
+	lvl := Var(fs, "level", level(1), parseLevel, "log level")
+	port := Int(fs, "port", 8080, "listen port")
+	debug := Bool(fs, "debug", false, "debug mode")
+	name := String(fs, "name", "test", "name")
+	timeout := Duration(fs, "timeout", time.Second, "timeout")
+
+	err := fs.Parse([]string{"-level=error", "-port", "9090", "-debug"})
+	assert.NoError(t, err)
+	assert.Equal(t, SourceFlag, lvl.Source)
+	assert.Equal(t, level(2), lvl.Value)
+	assert.Equal(t, &IntFlag{Source: SourceFlag, Value: 9090}, port)
+	assert.Equal(t, &BoolFlag{Source: SourceFlag, Value: true}, debug)
+	assert.Equal(t, &StringFlag{Value: "test"}, name)
+	assert.Equal(t, &DurationFlag{Value: time.Second}, timeout)
+	assert.Equal(t, "1s", fs.Lookup("timeout").DefValue)
+
+	assert.Error(t, fs.Parse([]string{"-level=fatal"}))
+	assert.Equal(t, level(2), lvl.Value)
+	assert.Error(t, fs.Parse([]string{"-port=abc"}))
+	assert.Equal(t, 9090, port.Value)
+}
+
+func TestNoParserFlag(t *testing.T) {
+	var f Flag[level]
+	assert.Error(t, f.Set("debug"))
+	assert.Equal(t, SourceDefault, f.Source)
+}
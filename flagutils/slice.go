@@ -0,0 +1,172 @@
+package flagutils
+
+import (
+	"flag"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// SliceFlag represents a flag of repeated values of type T and implements flag.Value interface.
+// Every use of the flag appends the values of the comma-separated list.
+// If Replace is set the first use of the flag replaces the default values.
+type SliceFlag[T any] struct {
+	// Source is SourceFlag when the flag is set, Resolver records the other sources
+	Source  Source
+	Value   []T
+	Replace bool
+
+	parse Parser[T]
+}
+
+// StringSliceFlag represents repeated string flag and implements flag.Value interface
+type StringSliceFlag = SliceFlag[string]
+
+// IntSliceFlag represents repeated int flag and implements flag.Value interface
+type IntSliceFlag = SliceFlag[int]
+
+// DurationSliceFlag represents repeated duration flag and implements flag.Value interface
+type DurationSliceFlag = SliceFlag[time.Duration]
+
+// NewSliceFlag returns a slice flag with the default values parsed by parse,
+// the parser of the basic type is used if parse is nil.
+func NewSliceFlag[T any](value []T, parse Parser[T]) *SliceFlag[T] {
+	return &SliceFlag[T]{Value: append([]T(nil), value...), parse: parse}
+}
+
+// Set parses the comma-separated list s and appends the values, empty items are skipped.
+func (f *SliceFlag[T]) Set(s string) error {
+	parse := f.parse
+	if parse == nil {
+		parse = basicParser[T]()
+	}
+	if parse == nil {
+		var zero T
+		return &ParseError{Input: s, Err: fmt.Errorf("no parser for %T", zero)}
+	}
+	var values []T
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item == "" {
+			continue
+		}
+		value, err := parse(item)
+		if err != nil {
+			var zero T
+			return newParseError(s, zero, err)
+		}
+		values = append(values, value)
+	}
+	if f.Replace && f.Source == SourceDefault {
+		f.Value = nil
+	}
+	f.Value = append(f.Value, values...)
+	f.Source = SourceFlag
+	return nil
+}
+
+func (f *SliceFlag[T]) String() string {
+	if f == nil {
+		return ""
+	}
+	items := make([]string, len(f.Value))
+	for i, value := range f.Value {
+		items[i] = formatValue(value)
+	}
+	return strings.Join(items, ",")
+}
+
+// Get returns the values of the flag and implements flag.Getter interface.
+func (f *SliceFlag[T]) Get() interface{} {
+	return f.Value
+}
+
+// StringMapFlag represents a flag of repeated key=value pairs and implements flag.Value interface.
+// Every use of the flag adds the pairs of the comma-separated list.
+// If Replace is set the first use of the flag replaces the default pairs.
+type StringMapFlag struct {
+	// Source is SourceFlag when the flag is set, Resolver records the other sources
+	Source  Source
+	Value   map[string]string
+	Replace bool
+}
+
+// Set parses the comma-separated list of key=value pairs s and adds them,
+// empty items are skipped.
+func (f *StringMapFlag) Set(s string) error {
+	values := make(map[string]string)
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item == "" {
+			continue
+		}
+		kv := strings.SplitN(item, "=", 2)
+		if len(kv) != 2 || kv[0] == "" {
+			return &ParseError{Input: s, Err: fmt.Errorf("invalid key=value pair %q", item)}
+		}
+		values[kv[0]] = kv[1]
+	}
+	if f.Value == nil || f.Replace && f.Source == SourceDefault {
+		f.Value = make(map[string]string)
+	}
+	for k, v := range values {
+		f.Value[k] = v
+	}
+	f.Source = SourceFlag
+	return nil
+}
+
+func (f *StringMapFlag) String() string {
+	if f == nil {
+		return ""
+	}
+	items := make([]string, 0, len(f.Value))
+	for k, v := range f.Value {
+		items = append(items, k+"="+v)
+	}
+	sort.Strings(items)
+	return strings.Join(items, ",")
+}
+
+// Get returns the pairs of the flag and implements flag.Getter interface.
+func (f *StringMapFlag) Get() interface{} {
+	return f.Value
+}
+
+// SliceVar defines a slice flag of type T with the specified name, default values, parser and usage
+// in fs (flag.CommandLine if fs is nil) and returns the flag.
+func SliceVar[T any](fs *flag.FlagSet, name string, value []T, parse Parser[T], usage string) *SliceFlag[T] {
+	f := NewSliceFlag(value, parse)
+	if fs == nil {
+		fs = flag.CommandLine
+	}
+	fs.Var(f, name, usage)
+	return f
+}
+
+// StringSlice defines a repeated string flag in fs and returns the flag.
+func StringSlice(fs *flag.FlagSet, name string, value []string, usage string) *StringSliceFlag {
+	return SliceVar(fs, name, value, nil, usage)
+}
+
+// IntSlice defines a repeated int flag in fs and returns the flag.
+func IntSlice(fs *flag.FlagSet, name string, value []int, usage string) *IntSliceFlag {
+	return SliceVar(fs, name, value, nil, usage)
+}
+
+// DurationSlice defines a repeated duration flag in fs and returns the flag.
+func DurationSlice(fs *flag.FlagSet, name string, value []time.Duration, usage string) *DurationSliceFlag {
+	return SliceVar(fs, name, value, nil, usage)
+}
+
+// StringMap defines a repeated key=value flag in fs and returns the flag.
+func StringMap(fs *flag.FlagSet, name string, value map[string]string, usage string) *StringMapFlag {
+	f := &StringMapFlag{Value: make(map[string]string, len(value))}
+	for k, v := range value {
+		f.Value[k] = v
+	}
+	if fs == nil {
+		fs = flag.CommandLine
+	}
+	fs.Var(f, name, usage)
+	return f
+}
@@ -0,0 +1,54 @@
+package flagutils
+
+import (
+	"flag"
+	"io/ioutil"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSliceFlags(t *testing.T) {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.SetOutput(ioutil.Discard)
+	defaults := []string{"localhost:1"}
+	peers := StringSlice(fs, "peer", defaults, "peers")
+	ports := IntSlice(fs, "port", []int{80}, "ports")
+	ports.Replace = true
+	timeouts := DurationSlice(fs, "timeout", nil, "timeouts")
+	unused := IntSlice(fs, "unused", []int{1, 2}, "unused")
+	labels := StringMap(fs, "label", map[string]string{"env": "dev"}, "labels")
+
+	err := fs.Parse([]string{
+		"-peer", "a", "-peer=b,c",
+		"-port=8080,8081", "-port", "9090",
+		"-timeout=1s,,2ms",
+		"-label", "env=prod,zone=a", "-label=role=db",
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"localhost:1", "a", "b", "c"}, peers.Value)
+	assert.Equal(t, []string{"localhost:1"}, defaults)
+	assert.Equal(t, SourceFlag, peers.Source)
+	assert.Equal(t, []int{8080, 8081, 9090}, ports.Value)
+	assert.Equal(t, []time.Duration{time.Second, 2 * time.Millisecond}, timeouts.Value)
+	assert.Equal(t, SourceDefault, unused.Source)
+	assert.Equal(t, []int{1, 2}, unused.Value)
+	assert.Equal(t, "1,2", fs.Lookup("unused").DefValue)
+	assert.Equal(t, map[string]string{"env": "prod", "zone": "a", "role": "db"}, labels.Value)
+	assert.Equal(t, "env=prod,role=db,zone=a", labels.String())
+
+	assert.Error(t, fs.Parse([]string{"-port=1,x"}))
+	assert.Equal(t, []int{8080, 8081, 9090}, ports.Value)
+	assert.Error(t, fs.Parse([]string{"-label=novalue"}))
+}
+
+func TestZeroSliceFlags(t *testing.T) {
+	var peers StringSliceFlag
+	var labels StringMapFlag
+	assert.Equal(t, "", peers.String())
+	assert.NoError(t, peers.Set("a,b"))
+	assert.Equal(t, &StringSliceFlag{Source: SourceFlag, Value: []string{"a", "b"}}, &peers)
+	assert.NoError(t, labels.Set("k=v"))
+	assert.Equal(t, &StringMapFlag{Source: SourceFlag, Value: map[string]string{"k": "v"}}, &labels)
+}
@@ -0,0 +1,216 @@
+package flagutils
+
+import (
+	"flag"
+	"os"
+	"strings"
+	"unicode"
+)
+
+// Source defines where the value of a flag came from.
+type Source int
+
+const (
+	// SourceDefault means the flag has the default value.
+	SourceDefault Source = iota
+	// SourceFlag means the value is passed on the command line.
+	SourceFlag
+	// SourceEnv means the value is taken from an environment variable.
+	SourceEnv
+	// SourceFile means the value is taken from a config file.
+	SourceFile
+)
+
+var sourceNames = []string{
+	SourceDefault: "default",
+	SourceFlag:    "flag",
+	SourceEnv:     "env",
+	SourceFile:    "file",
+}
+
+// String returns the name of the source.
+func (s Source) String() string {
+	if s < 0 || int(s) >= len(sourceNames) {
+		return "unknown"
+	}
+	return sourceNames[s]
+}
+
+// sourced is implemented by the flags of this package to record the source of the value.
+type sourced interface {
+	source() Source
+	setSource(s Source)
+}
+
+func (f *Flag[T]) source() Source           { return f.Source }
+func (f *Flag[T]) setSource(s Source)       { f.Source = s }
+func (f *SliceFlag[T]) source() Source      { return f.Source }
+func (f *SliceFlag[T]) setSource(s Source)  { f.Source = s }
+func (f *StringMapFlag) source() Source     { return f.Source }
+func (f *StringMapFlag) setSource(s Source) { f.Source = s }
+
+// sourcedValue wraps a flag of the flag package set by Resolver to record its source,
+// the source becomes SourceFlag when the flag is set again.
+type sourcedValue struct {
+	flag.Value
+	src Source
+}
+
+// boolSourcedValue is sourcedValue of a boolean flag.
+type boolSourcedValue struct {
+	*sourcedValue
+}
+
+func (v boolSourcedValue) IsBoolFlag() bool {
+	return true
+}
+
+func (v *sourcedValue) Set(s string) error {
+	if err := v.Value.Set(s); err != nil {
+		return err
+	}
+	v.src = SourceFlag
+	return nil
+}
+
+// Get returns the value of the wrapped flag and implements flag.Getter interface.
+func (v *sourcedValue) Get() interface{} {
+	if g, ok := v.Value.(flag.Getter); ok {
+		return g.Get()
+	}
+	return v.Value.String()
+}
+
+func (v *sourcedValue) source() Source     { return v.src }
+func (v *sourcedValue) setSource(s Source) { v.src = s }
+
+// withSource returns the value of the flag which records its source,
+// the flags of the flag package are wrapped in sourcedValue.
+func withSource(f *flag.Flag) sourced {
+	if s, ok := f.Value.(sourced); ok {
+		return s
+	}
+	v := &sourcedValue{Value: f.Value}
+	if b, ok := f.Value.(interface{ IsBoolFlag() bool }); ok && b.IsBoolFlag() {
+		f.Value = boolSourcedValue{v}
+	} else {
+		f.Value = v
+	}
+	return v
+}
+
+// SourceOf returns the source of the value of a flag defined by this package
+// or set by Resolver, other flags always have SourceDefault,
+// use Effective to get the sources of all flags.
+func SourceOf(v flag.Value) Source {
+	if s, ok := v.(sourced); ok {
+		return s.source()
+	}
+	return SourceDefault
+}
+
+// sourceOf returns the source recorded by the flag,
+// or SourceFlag if the flag of the flag package is passed on the command line.
+func sourceOf(fs *flag.FlagSet, f *flag.Flag) Source {
+	if s := SourceOf(f.Value); s != SourceDefault {
+		return s
+	}
+	passed := false
+	fs.Visit(func(ff *flag.Flag) {
+		if ff == f {
+			passed = true
+		}
+	})
+	if passed {
+		return SourceFlag
+	}
+	return SourceDefault
+}
+
+// Resolver fills the flags which are not passed on the command line
+// from environment variables and a config file.
+type Resolver struct {
+	// Prefix is the prefix of environment variables, the variable of the flag
+	// "listen-addr" with the prefix "chat" is CHAT_LISTEN_ADDR
+	Prefix string
+	// File is the path of JSON (.json), YAML (.yaml, .yml) or INI-style config file
+	// with the values keyed by flag names, no file if omitted
+	File string
+	// EnvNames overrides the names of environment variables by flag names
+	EnvNames map[string]string
+	// LookupEnv is os.LookupEnv if omitted
+	LookupEnv func(key string) (string, bool)
+}
+
+// Resolve sets the flags of fs (flag.CommandLine if fs is nil) after parsing.
+// Every flag is taken from the command line, then from the environment variable
+// and then from the config file. The source of the value is recorded in the flag,
+// the flags of the flag package set by Resolver are wrapped to record it.
+func (r *Resolver) Resolve(fs *flag.FlagSet) error {
+	if fs == nil {
+		fs = flag.CommandLine
+	}
+	lookupEnv := r.LookupEnv
+	if lookupEnv == nil {
+		lookupEnv = os.LookupEnv
+	}
+	var values map[string]string
+	if r.File != "" {
+		var err error
+		if values, err = readConfig(r.File); err != nil {
+			return err
+		}
+	}
+
+	passed := make(map[string]bool)
+	fs.Visit(func(f *flag.Flag) {
+		passed[f.Name] = true
+	})
+	var err error
+	fs.VisitAll(func(f *flag.Flag) {
+		if err != nil || passed[f.Name] {
+			return
+		}
+		if value, ok := lookupEnv(r.EnvName(f.Name)); ok {
+			err = setFrom(f, value, SourceEnv)
+		} else if value, ok := values[f.Name]; ok {
+			err = setFrom(f, value, SourceFile)
+		}
+	})
+	return err
+}
+
+// EnvName returns the name of the environment variable of the flag.
+func (r *Resolver) EnvName(name string) string {
+	if env, ok := r.EnvNames[name]; ok {
+		return env
+	}
+	if r.Prefix != "" {
+		name = r.Prefix + "_" + name
+	}
+	return strings.Map(func(c rune) rune {
+		if unicode.IsLetter(c) || unicode.IsDigit(c) {
+			return unicode.ToUpper(c)
+		}
+		return '_'
+	}, name)
+}
+
+// Resolve sets the flags of fs which are not passed on the command line
+// from PREFIX_NAME environment variables and the config file (if file is not empty).
+func Resolve(fs *flag.FlagSet, prefix, file string) error {
+	r := Resolver{Prefix: prefix, File: file}
+	return r.Resolve(fs)
+}
+
+// setFrom sets the value of the flag and records the source in the flag.
+func setFrom(f *flag.Flag, value string, source Source) error {
+	v := withSource(f)
+	if err := f.Value.Set(value); err != nil {
+		perr := newParseError(value, nil, err)
+		perr.Flag = f.Name
+		return perr
+	}
+	v.setSource(source)
+	return nil
+}
@@ -0,0 +1,95 @@
+package flagutils
+
+import (
+	"flag"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResolve(t *testing.T) {
+	dir, err := ioutil.TempDir("", "flagutils")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	file := filepath.Join(dir, "chat.json")
+	err = ioutil.WriteFile(file, []byte(`{"addr": ":9000", "timeout": "5s", "peer": ["a", "b"], "debug": true}`), 0644)
+	assert.NoError(t, err)
+
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.SetOutput(ioutil.Discard)
+	addr := String(fs, "addr", ":8822", "listen address")
+	token := String(fs, "bot-token", "", "token")
+	timeout := Duration(fs, "timeout", time.Second, "timeout")
+	peers := StringSlice(fs, "peer", nil, "peers")
+	debug := Bool(fs, "debug", false, "debug mode")
+	retries := Int(fs, "retries", 3, "retries")
+	var plain string
+	fs.StringVar(&plain, "plain", "", "plain flag")
+	verbose := fs.Bool("verbose", false, "plain bool flag")
+
+	env := map[string]string{
+		"CHAT_ADDR":      ":7000",
+		"CHAT_BOT_TOKEN": "secret",
+		"CHAT_DEBUG":     "false",
+		"CHAT_PLAIN":     "from env",
+		"CHAT_VERBOSE":   "true",
+	}
+	r := Resolver{
+		Prefix: "chat",
+		File:   file,
+		LookupEnv: func(key string) (string, bool) {
+			v, ok := env[key]
+			return v, ok
+		},
+	}
+	assert.NoError(t, fs.Parse([]string{"-debug"}))
+	assert.NoError(t, r.Resolve(fs))
+
+	assert.Equal(t, &StringFlag{Source: SourceEnv, Value: ":7000"}, addr)
+	assert.Equal(t, &StringFlag{Source: SourceEnv, Value: "secret"}, token)
+	assert.Equal(t, &DurationFlag{Source: SourceFile, Value: 5 * time.Second}, timeout)
+	assert.Equal(t, []string{"a", "b"}, peers.Value)
+	assert.Equal(t, SourceFile, SourceOf(peers))
+	assert.Equal(t, &BoolFlag{Source: SourceFlag, Value: true}, debug)
+	assert.Equal(t, &IntFlag{Source: SourceDefault, Value: 3}, retries)
+	assert.Equal(t, "from env", plain)
+	assert.True(t, *verbose)
+	// the flags of the flag package set by Resolver are wrapped to record their sources
+	assert.Equal(t, SourceEnv, SourceOf(fs.Lookup("plain").Value))
+	assert.Equal(t, "from env", fs.Lookup("plain").Value.(flag.Getter).Get())
+	assert.True(t, fs.Lookup("verbose").Value.(interface{ IsBoolFlag() bool }).IsBoolFlag())
+	assert.NoError(t, NewValidator(fs).Required("plain", "debug").Validate())
+	for _, state := range Effective(fs) {
+		if state.Name == "plain" {
+			assert.Equal(t, FlagState{Name: "plain", Value: "from env", Exist: true, Source: SourceEnv}, state)
+		}
+	}
+
+	// the flags set after resolving are passed as on the command line
+	assert.NoError(t, fs.Set("plain", "set"))
+	assert.NoError(t, fs.Set("addr", ":6000"))
+	assert.Equal(t, "set", plain)
+	assert.Equal(t, SourceFlag, SourceOf(fs.Lookup("plain").Value))
+	assert.Equal(t, SourceFlag, addr.Source)
+
+	env["CHAT_RETRIES"] = "many"
+	assert.Error(t, r.Resolve(fs))
+}
+
+func TestEnvName(t *testing.T) {
+	r := Resolver{Prefix: "tgbot"}
+	assert.Equal(t, "TGBOT_T", r.EnvName("t"))
+	assert.Equal(t, "TGBOT_DST_PATH", r.EnvName("dst-path"))
+	r.Prefix = ""
+	assert.Equal(t, "LOG_LEVEL", r.EnvName("log.level"))
+}
+
+func TestSourceString(t *testing.T) {
+	assert.Equal(t, "default", SourceDefault.String())
+	assert.Equal(t, "env", SourceEnv.String())
+	assert.Equal(t, "unknown", Source(10).String())
+}
@@ -0,0 +1,217 @@
+package flagutils
+
+import (
+	"flag"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/austinov/go-recipes/mathutils"
+)
+
+// ByteSize is a number of bytes parsed from human readable sizes like "512MiB" or "1.5GB".
+type ByteSize uint64
+
+// ParseByteSize parses the size with the optional IEC (KiB, MiB, ..., EiB) or SI (kB, MB, ..., EB) unit
+// like mathutils.ParseHumanBytes, the units are case-insensitive and the letters "iB" or "B"
+// may be omitted ("512M", "2Gi").
+func ParseByteSize(s string) (ByteSize, error) {
+	n, err := mathutils.ParseHumanBytes(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid byte size %q: %v", s, err)
+	}
+	return ByteSize(n), nil
+}
+
+// String returns the text of mathutils.FormatBytes in IEC or SI units with the fewest digits
+// which is parsed back to the same size, e.g. "1.5 KiB" or "300 MB", or the number of bytes.
+func (b ByteSize) String() string {
+	text := strconv.FormatUint(uint64(b), 10) + " B"
+	for _, units := range []mathutils.Units{mathutils.IEC, mathutils.SI} {
+		s := mathutils.FormatBytes(uint64(b), units, -1)
+		if n, err := mathutils.ParseHumanBytes(s); err == nil && n == uint64(b) && countDigits(s) < countDigits(text) {
+			text = s
+		}
+	}
+	return text
+}
+
+func countDigits(s string) int {
+	n := 0
+	for _, c := range s {
+		if c >= '0' && c <= '9' {
+			n++
+		}
+	}
+	return n
+}
+
+// HostPort is a network address "host:port", the host may be empty.
+type HostPort struct {
+	Host string
+	Port uint16
+}
+
+// ParseHostPort parses the address "host:port" with the numeric port.
+func ParseHostPort(s string) (HostPort, error) {
+	host, port, err := net.SplitHostPort(s)
+	if err != nil {
+		return HostPort{}, err
+	}
+	n, err := strconv.ParseUint(port, 10, 16)
+	if err != nil {
+		return HostPort{}, fmt.Errorf("invalid port in address %q", s)
+	}
+	return HostPort{Host: host, Port: uint16(n)}, nil
+}
+
+func (h HostPort) String() string {
+	if h == (HostPort{}) {
+		return ""
+	}
+	return net.JoinHostPort(h.Host, strconv.Itoa(int(h.Port)))
+}
+
+// ExistingPath is a path of an existing file or directory.
+type ExistingPath string
+
+// ParseExistingPath returns the path if the file exists.
+func ParseExistingPath(s string) (ExistingPath, error) {
+	if _, err := os.Stat(s); err != nil {
+		return "", err
+	}
+	return ExistingPath(s), nil
+}
+
+func parseTime(s string) (time.Time, error) {
+	return time.Parse(time.RFC3339Nano, s)
+}
+
+func parseURL(s string) (*url.URL, error) {
+	u, err := url.Parse(s)
+	if err != nil {
+		return nil, err
+	}
+	if u.Scheme == "" || u.Host == "" && u.Opaque == "" {
+		return nil, fmt.Errorf("invalid URL %q: scheme and host are required", s)
+	}
+	return u, nil
+}
+
+func parseIP(s string) (net.IP, error) {
+	ip := net.ParseIP(s)
+	if ip == nil {
+		return nil, fmt.Errorf("invalid IP address %q", s)
+	}
+	return ip, nil
+}
+
+func parseCIDR(s string) (*net.IPNet, error) {
+	_, ipnet, err := net.ParseCIDR(s)
+	return ipnet, err
+}
+
+// EnumParser returns the parser of strings which accepts only the choices.
+func EnumParser(choices ...string) Parser[string] {
+	return func(s string) (string, error) {
+		for _, c := range choices {
+			if s == c {
+				return s, nil
+			}
+		}
+		return "", fmt.Errorf("not one of %s", strings.Join(choices, ", "))
+	}
+}
+
+// formatValue returns the text of the value which is accepted by the parser of the type.
+func formatValue(value interface{}) string {
+	switch v := value.(type) {
+	case time.Time:
+		if v.IsZero() {
+			return ""
+		}
+		return v.Format(time.RFC3339Nano)
+	case *url.URL:
+		if v == nil {
+			return ""
+		}
+		return v.String()
+	case *net.IPNet:
+		if v == nil {
+			return ""
+		}
+		return v.String()
+	case net.IP:
+		if v == nil {
+			return ""
+		}
+		return v.String()
+	}
+	return fmt.Sprintf("%v", value)
+}
+
+// ByteSizeFlag represents byte size flag and implements flag.Value interface
+type ByteSizeFlag = Flag[ByteSize]
+
+// TimeFlag represents RFC3339 time flag and implements flag.Value interface
+type TimeFlag = Flag[time.Time]
+
+// URLFlag represents absolute URL flag and implements flag.Value interface
+type URLFlag = Flag[*url.URL]
+
+// IPFlag represents IP address flag and implements flag.Value interface
+type IPFlag = Flag[net.IP]
+
+// CIDRFlag represents CIDR network flag and implements flag.Value interface
+type CIDRFlag = Flag[*net.IPNet]
+
+// HostPortFlag represents "host:port" flag and implements flag.Value interface
+type HostPortFlag = Flag[HostPort]
+
+// PathFlag represents the flag of existing path and implements flag.Value interface
+type PathFlag = Flag[ExistingPath]
+
+// Bytes defines a byte size flag in fs and returns the flag.
+func Bytes(fs *flag.FlagSet, name string, value ByteSize, usage string) *ByteSizeFlag {
+	return Var(fs, name, value, nil, usage)
+}
+
+// Time defines a RFC3339 time flag in fs and returns the flag.
+func Time(fs *flag.FlagSet, name string, value time.Time, usage string) *TimeFlag {
+	return Var(fs, name, value, nil, usage)
+}
+
+// URL defines an absolute URL flag in fs and returns the flag.
+func URL(fs *flag.FlagSet, name string, value *url.URL, usage string) *URLFlag {
+	return Var(fs, name, value, nil, usage)
+}
+
+// IP defines an IP address flag in fs and returns the flag.
+func IP(fs *flag.FlagSet, name string, value net.IP, usage string) *IPFlag {
+	return Var(fs, name, value, nil, usage)
+}
+
+// CIDR defines a CIDR network flag in fs and returns the flag.
+func CIDR(fs *flag.FlagSet, name string, value *net.IPNet, usage string) *CIDRFlag {
+	return Var(fs, name, value, nil, usage)
+}
+
+// Addr defines a "host:port" flag in fs and returns the flag.
+func Addr(fs *flag.FlagSet, name string, value HostPort, usage string) *HostPortFlag {
+	return Var(fs, name, value, nil, usage)
+}
+
+// Path defines the flag of existing path in fs and returns the flag,
+// the default value is not checked.
+func Path(fs *flag.FlagSet, name string, value ExistingPath, usage string) *PathFlag {
+	return Var(fs, name, value, nil, usage)
+}
+
+// Enum defines a string flag which accepts only the choices in fs and returns the flag.
+func Enum(fs *flag.FlagSet, name string, value string, choices []string, usage string) *StringFlag {
+	return Var(fs, name, value, EnumParser(choices...), usage)
+}
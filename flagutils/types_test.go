@@ -0,0 +1,117 @@
+package flagutils
+
+import (
+	"flag"
+	"io/ioutil"
+	"math"
+	"net"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseByteSize(t *testing.T) {
+	cases := []struct {
+		input    string
+		expected ByteSize
+		str      string
+	}{
+		{"0", 0, "0 B"},
+		{"100", 100, "100 B"},
+		{"1536", 1536, "1.5 KiB"},
+		{"512MiB", 512 << 20, "512 MiB"},
+		{"512mib", 512 << 20, "512 MiB"},
+		{"2Gi", 2 << 30, "2 GiB"},
+		{"1.5 GiB", 3 << 29, "1.5 GiB"},
+		{"300MB", 300e6, "300 MB"},
+		{"1.5k", 1500, "1.5 kB"},
+		{"2kB", 2000, "2 kB"},
+		{"2e6", 2e6, "2 MB"},
+		{"1024 KiB", 1 << 20, "1 MiB"},
+		{"1234567", 1234567, "1234567 B"},
+		{"18446744073709551615", math.MaxUint64, "18446744073709551615 B"},
+		{"16EiB", 0, ""},
+		{"15EiB", 15 << 60, "15 EiB"},
+		{"abc", 0, ""},
+		{"10XB", 0, ""},
+	}
+	for _, c := range cases {
+		size, err := ParseByteSize(c.input)
+		if c.str == "" {
+			assert.Error(t, err, c.input)
+			continue
+		}
+		assert.NoError(t, err, c.input)
+		assert.Equal(t, c.expected, size, c.input)
+		assert.Equal(t, c.str, size.String(), c.input)
+		again, err := ParseByteSize(size.String())
+		assert.NoError(t, err, c.input)
+		assert.Equal(t, size, again, c.input)
+	}
+}
+
+func TestExtendedFlags(t *testing.T) {
+	dir, err := ioutil.TempDir("", "flagutils")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.SetOutput(ioutil.Discard)
+	size := Bytes(fs, "size", 64<<20, "size")
+	since := Time(fs, "since", time.Time{}, "since")
+	endpoint := URL(fs, "url", nil, "url")
+	ip := IP(fs, "ip", net.IPv4(127, 0, 0, 1), "ip")
+	network := CIDR(fs, "net", nil, "network")
+	addr := Addr(fs, "addr", HostPort{Port: 8822}, "address")
+	path := Path(fs, "path", "", "path")
+	algo := Enum(fs, "a", "bcrypt", []string{"bcrypt", "md5"}, "algorithm")
+
+	assert.Equal(t, "64 MiB", fs.Lookup("size").DefValue)
+	assert.Equal(t, "127.0.0.1", fs.Lookup("ip").DefValue)
+	assert.Equal(t, ":8822", fs.Lookup("addr").DefValue)
+	assert.Equal(t, "", fs.Lookup("url").DefValue)
+
+	err = fs.Parse([]string{
+		"-size=1GiB",
+		"-since=2016-10-17T10:00:00.5+03:00",
+		"-url=https://api.telegram.org/bot",
+		"-ip=::1",
+		"-net=10.1.0.0/16",
+		"-addr=localhost:9000",
+		"-path=" + dir,
+		"-a=md5",
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, &ByteSizeFlag{Source: SourceFlag, Value: 1 << 30}, size)
+	assert.Equal(t, SourceFlag, since.Source)
+	assert.Equal(t, "2016-10-17T10:00:00.5+03:00", since.String())
+	assert.Equal(t, "api.telegram.org", endpoint.Value.Host)
+	assert.Equal(t, "https://api.telegram.org/bot", endpoint.String())
+	assert.Equal(t, "::1", ip.String())
+	assert.Equal(t, "10.1.0.0/16", network.String())
+	assert.Equal(t, HostPort{Host: "localhost", Port: 9000}, addr.Value)
+	assert.Equal(t, "localhost:9000", addr.String())
+	assert.Equal(t, ExistingPath(dir), path.Value)
+	assert.Equal(t, "md5", algo.Value)
+
+	// every value is parsed back from its text
+	fs.VisitAll(func(f *flag.Flag) {
+		assert.NoError(t, f.Value.Set(f.Value.String()), f.Name)
+	})
+
+	for _, args := range [][]string{
+		{"-size=1XB"},
+		{"-since=yesterday"},
+		{"-url=/relative/path"},
+		{"-ip=300.0.0.1"},
+		{"-net=10.1.0.0"},
+		{"-addr=localhost"},
+		{"-addr=localhost:http"},
+		{"-path=" + dir + "/missing"},
+		{"-a=sha1"},
+	} {
+		assert.Error(t, fs.Parse(args), args[0])
+	}
+}
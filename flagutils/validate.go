@@ -0,0 +1,289 @@
+package flagutils
+
+import (
+	"cmp"
+	"errors"
+	"flag"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+)
+
+// Constraint checks the value of a flag, the values of SliceFlag are checked item by item.
+type Constraint func(value interface{}) error
+
+// Validator checks the constraints of the flags of a FlagSet after parsing.
+type Validator struct {
+	fs     *flag.FlagSet
+	checks []func() error
+}
+
+// ValidationError is the list of all failed constraints.
+type ValidationError struct {
+	Errors []error
+}
+
+func (e *ValidationError) Error() string {
+	msgs := make([]string, len(e.Errors))
+	for i, err := range e.Errors {
+		msgs[i] = err.Error()
+	}
+	return strings.Join(msgs, "\n")
+}
+
+// Unwrap returns the failed constraints.
+func (e *ValidationError) Unwrap() []error {
+	return e.Errors
+}
+
+// NewValidator returns a Validator of fs (flag.CommandLine if fs is nil).
+func NewValidator(fs *flag.FlagSet) *Validator {
+	if fs == nil {
+		fs = flag.CommandLine
+	}
+	return &Validator{fs: fs}
+}
+
+// Check adds the constraints of the flag, they are checked if the flag is set
+// (passed on the command line or resolved by Resolver).
+func (v *Validator) Check(name string, constraints ...Constraint) *Validator {
+	v.checks = append(v.checks, func() error {
+		f := v.fs.Lookup(name)
+		if f == nil {
+			return fmt.Errorf("flag -%s is not defined", name)
+		}
+		if !isSet(v.fs, f) {
+			return nil
+		}
+		getter, ok := f.Value.(flag.Getter)
+		if !ok {
+			return fmt.Errorf("flag -%s: value can not be checked", name)
+		}
+		for _, c := range constraints {
+			if err := checkItems(f.Value, getter.Get(), c); err != nil {
+				return fmt.Errorf("flag -%s: %v", name, err)
+			}
+		}
+		return nil
+	})
+	return v
+}
+
+// Required adds the check that every of the flags is set.
+func (v *Validator) Required(names ...string) *Validator {
+	for _, name := range names {
+		v.checks = append(v.checks, func() error {
+			if f := v.fs.Lookup(name); f == nil || !isSet(v.fs, f) {
+				return fmt.Errorf("flag -%s is required", name)
+			}
+			return nil
+		})
+	}
+	return v
+}
+
+// Exclusive adds the check that at most one of the flags is set.
+func (v *Validator) Exclusive(names ...string) *Validator {
+	v.checks = append(v.checks, func() error {
+		var set []string
+		for _, name := range names {
+			if f := v.fs.Lookup(name); f != nil && isSet(v.fs, f) {
+				set = append(set, "-"+name)
+			}
+		}
+		if len(set) > 1 {
+			return fmt.Errorf("flags %s are mutually exclusive", strings.Join(set, ", "))
+		}
+		return nil
+	})
+	return v
+}
+
+// Validate checks all constraints and returns *ValidationError with all failures.
+func (v *Validator) Validate() error {
+	var errs []error
+	for _, check := range v.checks {
+		if err := check(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if len(errs) > 0 {
+		return &ValidationError{Errors: errs}
+	}
+	return nil
+}
+
+// Min is the constraint of the minimum value, the numbers of any integer
+// or float types are compared by their values, e.g. Min(1) checks Uint64 flag.
+func Min[T cmp.Ordered](min T) Constraint {
+	return func(value interface{}) error {
+		c, err := compareTo(value, min)
+		if err != nil {
+			return err
+		}
+		if c < 0 {
+			return fmt.Errorf("%v is less than %v", value, min)
+		}
+		return nil
+	}
+}
+
+// Max is the constraint of the maximum value, the numbers of any integer
+// or float types are compared by their values, e.g. Max(16) checks Int64 flag.
+func Max[T cmp.Ordered](max T) Constraint {
+	return func(value interface{}) error {
+		c, err := compareTo(value, max)
+		if err != nil {
+			return err
+		}
+		if c > 0 {
+			return fmt.Errorf("%v is greater than %v", value, max)
+		}
+		return nil
+	}
+}
+
+// compareTo compares the value with the bound of the same type or of any numeric type.
+func compareTo[T cmp.Ordered](value interface{}, bound T) (int, error) {
+	if v, ok := value.(T); ok {
+		return cmp.Compare(v, bound), nil
+	}
+	if c, ok := compareNumbers(reflect.ValueOf(value), reflect.ValueOf(bound)); ok {
+		return c, nil
+	}
+	return 0, fmt.Errorf("%v is not %T", value, bound)
+}
+
+// compareNumbers compares the values of integer or float kinds exactly,
+// the floats are compared with the integers converted to float64.
+func compareNumbers(a, b reflect.Value) (int, bool) {
+	switch {
+	case isInt(a) && isInt(b):
+		return cmp.Compare(a.Int(), b.Int()), true
+	case isUint(a) && isUint(b):
+		return cmp.Compare(a.Uint(), b.Uint()), true
+	case isInt(a) && isUint(b):
+		if a.Int() < 0 {
+			return -1, true
+		}
+		return cmp.Compare(uint64(a.Int()), b.Uint()), true
+	case isUint(a) && isInt(b):
+		c, ok := compareNumbers(b, a)
+		return -c, ok
+	}
+	x, ok := toFloat(a)
+	if !ok {
+		return 0, false
+	}
+	y, ok := toFloat(b)
+	if !ok {
+		return 0, false
+	}
+	return cmp.Compare(x, y), true
+}
+
+func isInt(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return true
+	}
+	return false
+}
+
+func isUint(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return true
+	}
+	return false
+}
+
+func toFloat(v reflect.Value) (float64, bool) {
+	switch {
+	case isInt(v):
+		return float64(v.Int()), true
+	case isUint(v):
+		return float64(v.Uint()), true
+	case v.Kind() == reflect.Float32 || v.Kind() == reflect.Float64:
+		return v.Float(), true
+	}
+	return 0, false
+}
+
+// OneOf is the constraint of the allowed values.
+func OneOf[T comparable](choices ...T) Constraint {
+	return func(value interface{}) error {
+		for _, c := range choices {
+			if value == interface{}(c) {
+				return nil
+			}
+		}
+		items := make([]string, len(choices))
+		for i, c := range choices {
+			items[i] = fmt.Sprintf("%v", c)
+		}
+		return fmt.Errorf("%v is not one of %s", value, strings.Join(items, ", "))
+	}
+}
+
+// Match is the constraint of strings matching the regular expression.
+func Match(re *regexp.Regexp) Constraint {
+	return func(value interface{}) error {
+		s, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("%v is not string", value)
+		}
+		if !re.MatchString(s) {
+			return fmt.Errorf("%q does not match %s", s, re)
+		}
+		return nil
+	}
+}
+
+// NotEmpty is the constraint of non-empty strings.
+func NotEmpty() Constraint {
+	return func(value interface{}) error {
+		s, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("%v is not string", value)
+		}
+		if s == "" {
+			return errors.New("value is empty")
+		}
+		return nil
+	}
+}
+
+// itemized is implemented by SliceFlag to check its values item by item.
+type itemized interface {
+	items() []interface{}
+}
+
+func (f *SliceFlag[T]) items() []interface{} {
+	items := make([]interface{}, len(f.Value))
+	for i, v := range f.Value {
+		items[i] = v
+	}
+	return items
+}
+
+// checkItems checks every item of SliceFlag or the value of other flags,
+// e.g. net.IP is checked as a whole.
+func checkItems(f flag.Value, value interface{}, c Constraint) error {
+	s, ok := f.(itemized)
+	if !ok {
+		return c(value)
+	}
+	for _, item := range s.items() {
+		if err := c(item); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// isSet reports whether the flag is passed on the command line or resolved by Resolver.
+func isSet(fs *flag.FlagSet, f *flag.Flag) bool {
+	return sourceOf(fs, f) != SourceDefault
+}
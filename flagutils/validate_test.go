@@ -0,0 +1,165 @@
+package flagutils
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidate(t *testing.T) {
+	cases := []struct {
+		args     []string
+		env      map[string]string
+		expected string
+	}{
+		{
+			args: []string{"-t=text", "-a=md5", "-workers=16", "-port=80,443", "-name=chat", "-json", "-plain=x"},
+		},
+		{
+			expected: "flag -t is required",
+		},
+		{
+			args: []string{"-a=sha1", "-workers=0", "-timeout=2m", "-port=80,0", "-name=Chat", "-plain=z", "-json", "-yaml"},
+			expected: `flag -t is required
+flag -a: sha1 is not one of bcrypt, md5
+flag -workers: 0 is less than 1
+flag -timeout: 2m0s is greater than 1m0s
+flag -port: 0 is less than 1
+flag -name: "Chat" does not match ^[a-z]+$
+flag -plain: z is not one of x, y
+flags -json, -yaml are mutually exclusive`,
+		},
+		{
+			// resolved flags are checked as well
+			env:      map[string]string{"T": "text", "WORKERS": "100"},
+			expected: "flag -workers: 100 is greater than 16",
+		},
+	}
+	for _, c := range cases {
+		fs := flag.NewFlagSet("test", flag.ContinueOnError)
+		fs.SetOutput(ioutil.Discard)
+		String(fs, "a", "bcrypt", "hash algorithm")
+		String(fs, "t", "", "text")
+		Int(fs, "workers", 1, "workers")
+		Duration(fs, "timeout", time.Second, "timeout")
+		IntSlice(fs, "port", nil, "ports")
+		String(fs, "name", "", "name")
+		Bool(fs, "json", false, "json output")
+		Bool(fs, "yaml", false, "yaml output")
+		var plain string
+		fs.StringVar(&plain, "plain", "", "plain flag")
+		v := NewValidator(fs).
+			Required("t").
+			Check("a", OneOf("bcrypt", "md5")).
+			Check("workers", Min(1), Max(16)).
+			Check("timeout", Max(time.Minute)).
+			Check("port", Min(1), Max(65535)).
+			Check("name", Match(regexp.MustCompile(`^[a-z]+$`))).
+			Check("plain", OneOf("x", "y")).
+			Exclusive("json", "yaml")
+
+		assert.NoError(t, fs.Parse(c.args), c.args)
+		r := Resolver{LookupEnv: func(key string) (string, bool) {
+			value, ok := c.env[key]
+			return value, ok
+		}}
+		assert.NoError(t, r.Resolve(fs), c.args)
+		err := v.Validate()
+		if c.expected == "" {
+			assert.NoError(t, err, c.args)
+			continue
+		}
+		var verr *ValidationError
+		assert.True(t, errors.As(err, &verr), c.args)
+		assert.Equal(t, c.expected, err.Error(), c.args)
+	}
+}
+
+func TestValidateUndefined(t *testing.T) {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	err := NewValidator(fs).Check("x", Min(1)).Required("y").Validate()
+	assert.Equal(t, "flag -x is not defined\nflag -y is required", err.Error())
+}
+
+func TestValidateNumericKinds(t *testing.T) {
+	cases := []struct {
+		args     []string
+		expected string
+	}{
+		{
+			args:     []string{"-n=10", "-i=-5", "-ratio=0.5", "-level=200", "-s=1"},
+			expected: "flag -s: 1 is not int",
+		},
+		{
+			args: []string{"-n=11", "-i=6", "-ratio=1.5", "-level=201"},
+			expected: `flag -n: 11 is greater than 10
+flag -i: 6 is greater than 5
+flag -ratio: 1.5 is greater than 1
+flag -level: 201 is greater than 200`,
+		},
+		{
+			args: []string{"-n=0", "-i=-6", "-ratio=-0.1"},
+			expected: `flag -n: 0 is less than 1
+flag -i: -6 is less than -5
+flag -ratio: -0.1 is less than 0`,
+		},
+	}
+	for _, c := range cases {
+		fs := flag.NewFlagSet("test", flag.ContinueOnError)
+		fs.SetOutput(ioutil.Discard)
+		Uint(fs, "n", 1, "uint")
+		Int64(fs, "i", 0, "int64")
+		Float64(fs, "ratio", 0, "float64")
+		Uint8(fs, "level", 0, "uint8")
+		String(fs, "s", "", "string")
+		v := NewValidator(fs).
+			Check("n", Min(1), Max(10)).
+			Check("i", Min(-5), Max(uint(5))).
+			Check("ratio", Min(0), Max(1)).
+			Check("level", Max(200)).
+			Check("s", Min(1))
+
+		assert.NoError(t, fs.Parse(c.args), c.args)
+		err := v.Validate()
+		if assert.Error(t, err, c.args) {
+			assert.Equal(t, c.expected, err.Error(), c.args)
+		}
+	}
+}
+
+func TestValidateItems(t *testing.T) {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.SetOutput(ioutil.Discard)
+	IP(fs, "ip", nil, "ip")
+	StringSlice(fs, "peer", nil, "peers")
+	String(fs, "t", "", "text")
+	loopback := func(value interface{}) error {
+		ip, ok := value.(net.IP)
+		if !ok {
+			return fmt.Errorf("%v is not IP", value)
+		}
+		if !ip.IsLoopback() {
+			return fmt.Errorf("%v is not loopback", ip)
+		}
+		return nil
+	}
+	v := NewValidator(fs).
+		Check("ip", loopback).
+		Check("peer", NotEmpty(), OneOf("a", "b")).
+		Check("t", NotEmpty())
+
+	assert.NoError(t, fs.Parse([]string{"-ip=127.0.0.1", "-peer=a,b", "-t=text"}))
+	assert.NoError(t, v.Validate())
+
+	assert.NoError(t, fs.Parse([]string{"-ip=10.0.0.1", "-peer=c", "-t="}))
+	assert.Equal(t, `flag -ip: 10.0.0.1 is not loopback
+flag -peer: c is not one of a, b
+flag -t: value is empty`, v.Validate().Error())
+}
@@ -1,5 +1,7 @@
 package example
 
+import "github.com/austinov/go-recipes/mathutils"
+
 //genorm:vw_patients:view
 type PatientView struct {
 	Patient       `genorm:",embed"`
@@ -9,9 +11,10 @@ type PatientView struct {
 
 //genorm:patients
 type Patient struct {
-	Id          int64  `genorm:"id,pk"`
-	DoctorId    int32  `genorm:"doctor_id"`
-	PatientId   int32  `genorm:"patient_id"`
-	PulseTypeId int32  `genorm:"pulse_type_id"`
-	Diagnosis   string `genorm:"diagnosis"`
+	Id          int64             `genorm:"id,pk"`
+	DoctorId    int32             `genorm:"doctor_id"`
+	PatientId   int32             `genorm:"patient_id"`
+	PulseTypeId int32             `genorm:"pulse_type_id"`
+	Diagnosis   string            `genorm:"diagnosis"`
+	Fee         mathutils.Decimal `genorm:"fee"` // NUMERIC column
 }
@@ -6,10 +6,10 @@ import (
 )
 
 const (
-	selectPatientViewByIdSql = "SELECT id, doctor_id, patient_id, pulse_type_id, diagnosis, doctor_name, pulse_type_name FROM vw_patients WHERE id = $1"
-	selectPatientByIdSql     = "SELECT id, doctor_id, patient_id, pulse_type_id, diagnosis FROM patients WHERE id = $1"
-	insertPatientSql         = "INSERT INTO patients ( doctor_id, patient_id, pulse_type_id, diagnosis ) VALUES ( $1, $2, $3, $4 ) RETURNING id"
-	updatePatientSql         = "WITH rows AS (UPDATE patients SET doctor_id = $1, patient_id = $2, pulse_type_id = $3, diagnosis = $4 WHERE id = $5 RETURNING 1) SELECT count(*) FROM rows"
+	selectPatientViewByIdSql = "SELECT id, doctor_id, patient_id, pulse_type_id, diagnosis, fee, doctor_name, pulse_type_name FROM vw_patients WHERE id = $1"
+	selectPatientByIdSql     = "SELECT id, doctor_id, patient_id, pulse_type_id, diagnosis, fee FROM patients WHERE id = $1"
+	insertPatientSql         = "INSERT INTO patients ( doctor_id, patient_id, pulse_type_id, diagnosis, fee ) VALUES ( $1, $2, $3, $4, $5 ) RETURNING id"
+	updatePatientSql         = "WITH rows AS (UPDATE patients SET doctor_id = $1, patient_id = $2, pulse_type_id = $3, diagnosis = $4, fee = $5 WHERE id = $6 RETURNING 1) SELECT count(*) FROM rows"
 )
 
 var (
@@ -28,6 +28,7 @@ func getPatientViewById(id int64) (PatientView, error) {
 		&t.PatientId,
 		&t.PulseTypeId,
 		&t.Diagnosis,
+		&t.Fee,
 		&t.DoctorName,
 		&t.PulseTypeName)
 	if err != nil {
@@ -45,7 +46,8 @@ func getPatientById(id int64) (Patient, error) {
 		&t.DoctorId,
 		&t.PatientId,
 		&t.PulseTypeId,
-		&t.Diagnosis)
+		&t.Diagnosis,
+		&t.Fee)
 	if err != nil {
 		return t, err
 	}
@@ -58,7 +60,8 @@ func insertPatient(tx *sql.Tx, t *Patient) error {
 		t.DoctorId,
 		t.PatientId,
 		t.PulseTypeId,
-		t.Diagnosis)
+		t.Diagnosis,
+		t.Fee)
 	return row.Scan(&t.Id)
 }
 
@@ -68,6 +71,7 @@ func updatePatient(tx *sql.Tx, t *Patient) (int, error) {
 		t.PatientId,
 		t.PulseTypeId,
 		t.Diagnosis,
+		t.Fee,
 		t.Id)
 	var cnt int
 	if err := row.Scan(&cnt); err != nil {
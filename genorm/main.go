@@ -12,6 +12,8 @@ import (
 	"os/exec"
 	"path/filepath"
 	"strings"
+
+	"github.com/austinov/go-recipes/flagutils"
 )
 
 func main() {
@@ -21,12 +23,13 @@ func main() {
 		fmt.Fprintf(os.Stderr, "Flags:\n")
 		flag.PrintDefaults()
 	}
-	var (
-		dstPath string
-		dstPack string
-	)
-	flag.StringVar(&dstPath, "dst-path", "", "destination path to store files (if omitted will used source directories)")
-	flag.StringVar(&dstPack, "dst-pack", "", "destination package name (if omitted will used name from destination path)")
+	var cfg struct {
+		DstPath string `flag:"dst-path" usage:"destination path to store files (if omitted will used source directories)"`
+		DstPack string `flag:"dst-pack" usage:"destination package name (if omitted will used name from destination path)"`
+	}
+	if _, err := flagutils.Bind(flag.CommandLine, &cfg); err != nil {
+		log.Fatal(err)
+	}
 	flag.Parse()
 
 	wd, err := os.Getwd()
@@ -39,8 +42,8 @@ func main() {
 	for _, arg := range flag.Args() {
 		var (
 			pack    *build.Package
-			newPath = dstPath
-			newPack = dstPack
+			newPath = cfg.DstPath
+			newPack = cfg.DstPack
 		)
 		s, err := os.Stat(arg)
 		if err == nil && s.IsDir() {
@@ -59,7 +62,7 @@ func main() {
 			newPath = pack.Dir
 		}
 		if newPack == "" {
-			if newPack, err = getTargetPackageName(pack, dstPath); err != nil {
+			if newPack, err = getTargetPackageName(pack, cfg.DstPath); err != nil {
 				log.Fatalf("%s: %s", arg, err)
 			}
 		}
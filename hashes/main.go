@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"os"
 
+	"github.com/austinov/go-recipes/flagutils"
 	"golang.org/x/crypto/bcrypt"
 )
 
@@ -25,7 +26,12 @@ func main() {
 	flag.StringVar(&text, "t", "", "text to hash")
 	flag.Parse()
 
-	if text == "" {
+	v := flagutils.NewValidator(flag.CommandLine).
+		Required("t").
+		Check("t", flagutils.NotEmpty()).
+		Check("a", flagutils.OneOf("bcrypt", "md5"))
+	if err := v.Validate(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
 		flag.Usage()
 		os.Exit(1)
 	}
@@ -0,0 +1,203 @@
+package mathutils
+
+import "math"
+
+// Signed is the set of signed integer types.
+type Signed interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64
+}
+
+// Unsigned is the set of unsigned integer types.
+type Unsigned interface {
+	~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr
+}
+
+// Integer is the set of integer types.
+type Integer interface {
+	Signed | Unsigned
+}
+
+// bounds returns the minimum and maximum values of the integer type.
+func bounds[T Integer]() (T, T) {
+	bits := 0
+	for x := T(1); x != 0; x <<= 1 {
+		bits++
+	}
+	if ^T(0) < 0 {
+		min := T(1) << (bits - 1)
+		return min, ^min
+	}
+	return 0, ^T(0)
+}
+
+// MinOf returns the minimum value of the integer type.
+func MinOf[T Integer]() T {
+	min, _ := bounds[T]()
+	return min
+}
+
+// MaxOf returns the maximum value of the integer type.
+func MaxOf[T Integer]() T {
+	_, max := bounds[T]()
+	return max
+}
+
+// Add returns a + b and false if the sum overflows T.
+func Add[T Integer](a, b T) (T, bool) {
+	sum := a + b
+	if b > 0 && sum < a || b < 0 && sum > a {
+		return 0, false
+	}
+	return sum, true
+}
+
+// Sub returns a - b and false if the difference overflows T.
+func Sub[T Integer](a, b T) (T, bool) {
+	diff := a - b
+	if b > 0 && diff > a || b < 0 && diff < a {
+		return 0, false
+	}
+	return diff, true
+}
+
+// Mul returns a * b and false if the product overflows T.
+func Mul[T Integer](a, b T) (T, bool) {
+	if a == 0 || b == 0 {
+		return 0, true
+	}
+	// ^T(0) is -1 for signed types
+	if min := MinOf[T](); min < 0 && (a == ^T(0) && b == min || b == ^T(0) && a == min) {
+		return 0, false
+	}
+	product := a * b
+	if product/b != a {
+		return 0, false
+	}
+	return product, true
+}
+
+// Div returns a / b truncated toward zero and false if b is zero
+// or the quotient overflows T (the minimum value divided by -1).
+func Div[T Integer](a, b T) (T, bool) {
+	if b == 0 {
+		return 0, false
+	}
+	if min := MinOf[T](); min < 0 && a == min && b == ^T(0) {
+		return 0, false
+	}
+	return a / b, true
+}
+
+// Neg returns -a and false if the negation overflows T,
+// it is ok for unsigned types only for zero.
+func Neg[T Integer](a T) (T, bool) {
+	return Sub(T(0), a)
+}
+
+// Convert returns v converted to the type To and false if v is out of range of To.
+func Convert[To, From Integer](v From) (To, bool) {
+	t := To(v)
+	if From(t) != v || (t < 0) != (v < 0) {
+		return 0, false
+	}
+	return t, true
+}
+
+// SaturatingAdd returns a + b clamped to the range of T.
+func SaturatingAdd[T Integer](a, b T) T {
+	if sum, ok := Add(a, b); ok {
+		return sum
+	}
+	min, max := bounds[T]()
+	if b > 0 {
+		return max
+	}
+	return min
+}
+
+// SaturatingSub returns a - b clamped to the range of T.
+func SaturatingSub[T Integer](a, b T) T {
+	if diff, ok := Sub(a, b); ok {
+		return diff
+	}
+	min, max := bounds[T]()
+	if b > 0 {
+		return min
+	}
+	return max
+}
+
+// SaturatingMul returns a * b clamped to the range of T.
+func SaturatingMul[T Integer](a, b T) T {
+	if product, ok := Mul(a, b); ok {
+		return product
+	}
+	min, max := bounds[T]()
+	if (a < 0) != (b < 0) {
+		return min
+	}
+	return max
+}
+
+// SaturatingConvert returns v converted to the type To and clamped to its range.
+func SaturatingConvert[To, From Integer](v From) To {
+	if t, ok := Convert[To](v); ok {
+		return t
+	}
+	min, max := bounds[To]()
+	if v < 0 {
+		return min
+	}
+	return max
+}
+
+// Pow returns base**exp and false if the power overflows T.
+func Pow[T Integer](base T, exp uint) (T, bool) {
+	result := T(1)
+	for {
+		var ok bool
+		if exp&1 == 1 {
+			if result, ok = Mul(result, base); !ok {
+				return 0, false
+			}
+		}
+		if exp >>= 1; exp == 0 {
+			return result, true
+		}
+		// the square is multiplied into the result later, so its overflow is the overflow of the power
+		if base, ok = Mul(base, base); !ok {
+			return 0, false
+		}
+	}
+}
+
+// Sqrt returns the largest integer which square is not greater than n
+// and false if n is negative.
+func Sqrt[T Integer](n T) (T, bool) {
+	if n < 0 {
+		return 0, false
+	}
+	x := uint64(n)
+	// float64 is not exact for large numbers, so the root is corrected in integers
+	r := uint64(math.Sqrt(float64(x)))
+	for r > math.MaxUint32 || r*r > x {
+		r--
+	}
+	for r < math.MaxUint32 && (r+1)*(r+1) <= x {
+		r++
+	}
+	return T(r), true
+}
+
+// Log returns the largest integer k such as base**k is not greater than n
+// and false if n is not positive or base is less than 2.
+func Log[T Integer](n, base T) (int, bool) {
+	if n <= 0 || base < 2 {
+		return 0, false
+	}
+	k := 0
+	for ; n >= base; n /= base {
+		k++
+	}
+	return k, true
+}
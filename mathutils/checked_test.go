@@ -0,0 +1,398 @@
+package mathutils
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAdd(t *testing.T) {
+	cases := []struct {
+		a        int64
+		b        int64
+		expected int64
+		ok       bool
+	}{
+		{a: 0, b: 0, expected: 0, ok: true},
+		{a: 2, b: -3, expected: -1, ok: true},
+		{a: math.MaxInt64, b: 0, expected: math.MaxInt64, ok: true},
+		{a: math.MaxInt64, b: 1, ok: false},
+		{a: math.MaxInt64, b: math.MaxInt64, ok: false},
+		{a: math.MinInt64, b: -1, ok: false},
+		{a: math.MinInt64, b: math.MaxInt64, expected: -1, ok: true},
+		{a: math.MinInt64, b: math.MinInt64, ok: false},
+	}
+	for _, c := range cases {
+		n, ok := Add(c.a, c.b)
+		assert.Equal(t, c.ok, ok, c.a, c.b)
+		assert.Equal(t, c.expected, n, c.a, c.b)
+	}
+	unsigned := []struct {
+		a        uint8
+		b        uint8
+		expected uint8
+		ok       bool
+	}{
+		{a: 100, b: 155, expected: 255, ok: true},
+		{a: 100, b: 156, ok: false},
+		{a: 255, b: 255, ok: false},
+	}
+	for _, c := range unsigned {
+		n, ok := Add(c.a, c.b)
+		assert.Equal(t, c.ok, ok, c.a, c.b)
+		assert.Equal(t, c.expected, n, c.a, c.b)
+	}
+}
+
+func TestSub(t *testing.T) {
+	cases := []struct {
+		a        int32
+		b        int32
+		expected int32
+		ok       bool
+	}{
+		{a: 0, b: 0, expected: 0, ok: true},
+		{a: 2, b: 3, expected: -1, ok: true},
+		{a: 0, b: math.MaxInt32, expected: -math.MaxInt32, ok: true},
+		{a: -1, b: math.MaxInt32, expected: math.MinInt32, ok: true},
+		{a: -2, b: math.MaxInt32, ok: false},
+		{a: 0, b: math.MinInt32, ok: false},
+		{a: -1, b: math.MinInt32, expected: math.MaxInt32, ok: true},
+		{a: math.MaxInt32, b: -1, ok: false},
+	}
+	for _, c := range cases {
+		n, ok := Sub(c.a, c.b)
+		assert.Equal(t, c.ok, ok, c.a, c.b)
+		assert.Equal(t, c.expected, n, c.a, c.b)
+	}
+	unsigned := []struct {
+		a        uint64
+		b        uint64
+		expected uint64
+		ok       bool
+	}{
+		{a: 5, b: 5, expected: 0, ok: true},
+		{a: math.MaxUint64, b: 1, expected: math.MaxUint64 - 1, ok: true},
+		{a: 0, b: 1, ok: false},
+		{a: 1, b: math.MaxUint64, ok: false},
+	}
+	for _, c := range unsigned {
+		n, ok := Sub(c.a, c.b)
+		assert.Equal(t, c.ok, ok, c.a, c.b)
+		assert.Equal(t, c.expected, n, c.a, c.b)
+	}
+}
+
+func TestMul(t *testing.T) {
+	cases := []struct {
+		a        int64
+		b        int64
+		expected int64
+		ok       bool
+	}{
+		{a: 0, b: math.MinInt64, expected: 0, ok: true},
+		{a: -3, b: 7, expected: -21, ok: true},
+		{a: 1 << 31, b: 1 << 31, expected: 1 << 62, ok: true},
+		{a: 1 << 32, b: 1 << 31, ok: false},
+		{a: -1 << 32, b: 1 << 31, expected: math.MinInt64, ok: true},
+		{a: 3037000500, b: 3037000500, ok: false},
+		{a: math.MinInt64, b: -1, ok: false},
+		{a: -1, b: math.MinInt64, ok: false},
+		{a: math.MinInt64, b: 1, expected: math.MinInt64, ok: true},
+		{a: math.MaxInt64, b: -1, expected: -math.MaxInt64, ok: true},
+	}
+	for _, c := range cases {
+		n, ok := Mul(c.a, c.b)
+		assert.Equal(t, c.ok, ok, c.a, c.b)
+		assert.Equal(t, c.expected, n, c.a, c.b)
+	}
+	narrow := []struct {
+		a        int8
+		b        int8
+		expected int8
+		ok       bool
+	}{
+		{a: -16, b: 8, expected: math.MinInt8, ok: true},
+		{a: 16, b: 8, ok: false},
+		{a: 11, b: 11, expected: 121, ok: true},
+		{a: 12, b: 11, ok: false},
+		{a: math.MinInt8, b: -1, ok: false},
+	}
+	for _, c := range narrow {
+		n, ok := Mul(c.a, c.b)
+		assert.Equal(t, c.ok, ok, c.a, c.b)
+		assert.Equal(t, c.expected, n, c.a, c.b)
+	}
+	unsigned := []struct {
+		a        uint64
+		b        uint64
+		expected uint64
+		ok       bool
+	}{
+		{a: 1 << 32, b: 1<<32 - 1, expected: 1<<64 - 1<<32, ok: true},
+		{a: 1 << 32, b: 1 << 32, ok: false},
+		{a: math.MaxUint64, b: 1, expected: math.MaxUint64, ok: true},
+		{a: math.MaxUint64, b: 2, ok: false},
+	}
+	for _, c := range unsigned {
+		n, ok := Mul(c.a, c.b)
+		assert.Equal(t, c.ok, ok, c.a, c.b)
+		assert.Equal(t, c.expected, n, c.a, c.b)
+	}
+}
+
+func TestDiv(t *testing.T) {
+	cases := []struct {
+		a        int16
+		b        int16
+		expected int16
+		ok       bool
+	}{
+		{a: 7, b: 2, expected: 3, ok: true},
+		{a: -7, b: 2, expected: -3, ok: true},
+		{a: math.MinInt16, b: 1, expected: math.MinInt16, ok: true},
+		{a: math.MinInt16, b: -1, ok: false},
+		{a: math.MaxInt16, b: -1, expected: -math.MaxInt16, ok: true},
+		{a: 1, b: 0, ok: false},
+		{a: 0, b: 0, ok: false},
+	}
+	for _, c := range cases {
+		n, ok := Div(c.a, c.b)
+		assert.Equal(t, c.ok, ok, c.a, c.b)
+		assert.Equal(t, c.expected, n, c.a, c.b)
+	}
+	n, ok := Div(uint(math.MaxUint64), 2)
+	assert.True(t, ok)
+	assert.Equal(t, uint(math.MaxUint64/2), n)
+}
+
+func TestBounds(t *testing.T) {
+	assert.Equal(t, int8(math.MinInt8), MinOf[int8]())
+	assert.Equal(t, int8(math.MaxInt8), MaxOf[int8]())
+	assert.Equal(t, int64(math.MinInt64), MinOf[int64]())
+	assert.Equal(t, int64(math.MaxInt64), MaxOf[int64]())
+	assert.Equal(t, uint32(0), MinOf[uint32]())
+	assert.Equal(t, uint32(math.MaxUint32), MaxOf[uint32]())
+	assert.Equal(t, uint64(math.MaxUint64), MaxOf[uint64]())
+}
+
+func TestNeg(t *testing.T) {
+	cases := []struct {
+		input    int32
+		expected int32
+		ok       bool
+	}{
+		{
+			input:    0,
+			expected: 0,
+			ok:       true,
+		},
+		{
+			input:    -5,
+			expected: 5,
+			ok:       true,
+		},
+		{
+			input:    math.MaxInt32,
+			expected: -math.MaxInt32,
+			ok:       true,
+		},
+		{
+			input: math.MinInt32,
+			ok:    false,
+		},
+	}
+	for _, c := range cases {
+		n, ok := Neg(c.input)
+		assert.Equal(t, c.ok, ok, c.input)
+		assert.Equal(t, c.expected, n, c.input)
+	}
+	_, ok := Neg(uint(1))
+	assert.False(t, ok)
+}
+
+func TestConvert(t *testing.T) {
+	n32, ok := Convert[int32](int64(math.MaxInt32))
+	assert.True(t, ok)
+	assert.Equal(t, int32(math.MaxInt32), n32)
+	_, ok = Convert[int32](int64(math.MaxInt32 + 1))
+	assert.False(t, ok)
+	_, ok = Convert[int32](int64(math.MinInt32 - 1))
+	assert.False(t, ok)
+	n64, ok := Convert[int64](uint64(math.MaxInt64))
+	assert.True(t, ok)
+	assert.Equal(t, int64(math.MaxInt64), n64)
+	_, ok = Convert[int64](uint64(math.MaxInt64 + 1))
+	assert.False(t, ok)
+	_, ok = Convert[uint64](int64(-1))
+	assert.False(t, ok)
+	_, ok = Convert[uint8](int8(-128))
+	assert.False(t, ok)
+	u8, ok := Convert[uint8](int16(255))
+	assert.True(t, ok)
+	assert.Equal(t, uint8(255), u8)
+	i8, ok := Convert[int8](uint64(127))
+	assert.True(t, ok)
+	assert.Equal(t, int8(127), i8)
+	_, ok = Convert[int8](uint64(128))
+	assert.False(t, ok)
+	// every int16 is converted to the narrower types exactly when it is in their range
+	for v := math.MinInt16; v <= math.MaxInt16; v++ {
+		_, ok := Convert[int8](int16(v))
+		assert.Equal(t, v >= math.MinInt8 && v <= math.MaxInt8, ok, v)
+		_, ok = Convert[uint8](int16(v))
+		assert.Equal(t, v >= 0 && v <= math.MaxUint8, ok, v)
+	}
+}
+
+func TestSaturating(t *testing.T) {
+	assert.Equal(t, int8(math.MaxInt8), SaturatingAdd(int8(100), int8(100)))
+	assert.Equal(t, int8(math.MinInt8), SaturatingAdd(int8(-100), int8(-100)))
+	assert.Equal(t, int8(0), SaturatingAdd(int8(100), int8(-100)))
+	assert.Equal(t, uint8(math.MaxUint8), SaturatingAdd(uint8(200), uint8(100)))
+	assert.Equal(t, int64(math.MinInt64), SaturatingSub(int64(math.MinInt64), 1))
+	assert.Equal(t, int64(math.MaxInt64), SaturatingSub(int64(0), math.MinInt64))
+	assert.Equal(t, uint32(0), SaturatingSub(uint32(1), 2))
+	assert.Equal(t, int16(math.MaxInt16), SaturatingMul(int16(-300), int16(-300)))
+	assert.Equal(t, int16(math.MinInt16), SaturatingMul(int16(-300), int16(300)))
+	assert.Equal(t, int16(math.MaxInt16), SaturatingMul(int16(math.MinInt16), int16(-1)))
+	assert.Equal(t, uint64(math.MaxUint64), SaturatingMul(uint64(1<<32), uint64(1<<32)))
+	assert.Equal(t, int32(math.MaxInt32), SaturatingConvert[int32](int64(math.MaxInt64)))
+	assert.Equal(t, int32(math.MinInt32), SaturatingConvert[int32](int64(math.MinInt64)))
+	assert.Equal(t, uint16(0), SaturatingConvert[uint16](-1))
+	assert.Equal(t, int64(math.MaxInt64), SaturatingConvert[int64](uint64(math.MaxUint64)))
+	assert.Equal(t, int64(42), SaturatingConvert[int64](uint8(42)))
+}
+
+func TestPow(t *testing.T) {
+	cases := []struct {
+		base     int64
+		exp      uint
+		expected int64
+		ok       bool
+	}{
+		{
+			base:     0,
+			exp:      0,
+			expected: 1,
+			ok:       true,
+		},
+		{
+			base:     2,
+			exp:      62,
+			expected: 1 << 62,
+			ok:       true,
+		},
+		{
+			base: 2,
+			exp:  63,
+			ok:   false,
+		},
+		{
+			base:     -2,
+			exp:      63,
+			expected: math.MinInt64,
+			ok:       true,
+		},
+		{
+			base:     -1,
+			exp:      1<<64 - 1,
+			expected: -1,
+			ok:       true,
+		},
+		{
+			base:     10,
+			exp:      18,
+			expected: 1000000000000000000,
+			ok:       true,
+		},
+		{
+			base: 10,
+			exp:  19,
+			ok:   false,
+		},
+		{
+			base:     3,
+			exp:      39,
+			expected: 4052555153018976267,
+			ok:       true,
+		},
+		{
+			base: 3,
+			exp:  40,
+			ok:   false,
+		},
+	}
+	for _, c := range cases {
+		n, ok := Pow(c.base, c.exp)
+		assert.Equal(t, c.ok, ok, c.base, c.exp)
+		assert.Equal(t, c.expected, n, c.base, c.exp)
+	}
+	n, ok := Pow(uint64(2), 63)
+	assert.True(t, ok)
+	assert.Equal(t, uint64(1<<63), n)
+	_, ok = Pow(uint64(2), 64)
+	assert.False(t, ok)
+	i8, ok := Pow(int8(-2), 7)
+	assert.True(t, ok)
+	assert.Equal(t, int8(math.MinInt8), i8)
+	_, ok = Pow(int8(2), 7)
+	assert.False(t, ok)
+}
+
+func TestSqrt(t *testing.T) {
+	cases := []struct {
+		input    uint64
+		expected uint64
+	}{
+		{input: 0, expected: 0},
+		{input: 1, expected: 1},
+		{input: 3, expected: 1},
+		{input: 4, expected: 2},
+		{input: 99, expected: 9},
+		{input: 100, expected: 10},
+		{input: 1<<62 - 1, expected: 1<<31 - 1},
+		{input: 1 << 62, expected: 1 << 31},
+		{input: 4294967295 * 4294967295, expected: 4294967295},
+		{input: 4294967295*4294967295 - 1, expected: 4294967294},
+		{input: math.MaxUint64, expected: math.MaxUint32},
+	}
+	for _, c := range cases {
+		r, ok := Sqrt(c.input)
+		assert.True(t, ok)
+		assert.Equal(t, c.expected, r, c.input)
+	}
+	r, ok := Sqrt(int64(math.MaxInt64))
+	assert.True(t, ok)
+	assert.Equal(t, int64(3037000499), r)
+	_, ok = Sqrt(-1)
+	assert.False(t, ok)
+}
+
+func TestLog(t *testing.T) {
+	cases := []struct {
+		n        uint64
+		base     uint64
+		expected int
+		ok       bool
+	}{
+		{n: 1, base: 10, expected: 0, ok: true},
+		{n: 9, base: 10, expected: 0, ok: true},
+		{n: 10, base: 10, expected: 1, ok: true},
+		{n: 1 << 63, base: 2, expected: 63, ok: true},
+		{n: math.MaxUint64, base: 2, expected: 63, ok: true},
+		{n: math.MaxUint64, base: 10, expected: 19, ok: true},
+		{n: 1e19, base: 10, expected: 19, ok: true},
+		{n: 1e19 - 1, base: 10, expected: 18, ok: true},
+		{n: 0, base: 10, ok: false},
+		{n: 10, base: 1, ok: false},
+	}
+	for _, c := range cases {
+		k, ok := Log(c.n, c.base)
+		assert.Equal(t, c.ok, ok, c.n, c.base)
+		assert.Equal(t, c.expected, k, c.n, c.base)
+	}
+	_, ok := Log(-8, 2)
+	assert.False(t, ok)
+}
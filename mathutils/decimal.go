@@ -0,0 +1,343 @@
+package mathutils
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// maxDecimalDigits limits the exponent of parsed decimals, so "1e999999999" is not expanded in memory.
+// It is the number of digits before the point of PostgreSQL NUMERIC.
+const maxDecimalDigits = 131072
+
+// ErrDivisionByZero is returned by Decimal.Div when the divisor is zero.
+var ErrDivisionByZero = errors.New("mathutils: division by zero")
+
+// RoundingMode defines how Decimal is rounded when digits are dropped.
+type RoundingMode int
+
+const (
+	// RoundHalfUp rounds to the nearest, the half away from zero: 2.5 → 3, -2.5 → -3
+	RoundHalfUp RoundingMode = iota
+	// RoundHalfEven rounds to the nearest, the half to even (banker's rounding): 2.5 → 2, 3.5 → 4
+	RoundHalfEven
+	// RoundFloor rounds toward negative infinity: 2.7 → 2, -2.1 → -3
+	RoundFloor
+	// RoundCeil rounds toward positive infinity: 2.1 → 3, -2.7 → -2
+	RoundCeil
+	// RoundDown rounds toward zero (truncates): 2.7 → 2, -2.7 → -2
+	RoundDown
+)
+
+// Decimal is an exact fixed-point decimal number: the unscaled integer multiplied by 10^-scale,
+// e.g. 123.45 is 12345 with the scale 2. The integer is unbounded, so the arithmetic never overflows.
+// Decimal is immutable, the zero value is 0 with the scale 0.
+type Decimal struct {
+	value *big.Int
+	scale int32
+}
+
+// NewDecimal returns value * 10^-scale, e.g. NewDecimal(12345, 2) is 123.45.
+func NewDecimal(value int64, scale int32) Decimal {
+	checkScale(scale)
+	return Decimal{value: big.NewInt(value), scale: scale}
+}
+
+// NewDecimalFromBig returns value * 10^-scale, value is copied.
+func NewDecimalFromBig(value *big.Int, scale int32) Decimal {
+	checkScale(scale)
+	return Decimal{value: new(big.Int).Set(value), scale: scale}
+}
+
+// checkScale panics if the scale is negative.
+func checkScale(scale int32) {
+	if scale < 0 {
+		panic("mathutils: negative scale")
+	}
+}
+
+// ParseDecimal parses the decimal number like "-123.450" or "+7", the scale is
+// the number of digits after the point. The exponent is accepted too, e.g. "1.5e-3" is 0.0015.
+func ParseDecimal(s string) (Decimal, error) {
+	invalid := fmt.Errorf("mathutils: invalid decimal %q", s)
+	mantissa, exp := s, int64(0)
+	if i := strings.IndexAny(s, "eE"); i >= 0 {
+		var err error
+		if exp, err = strconv.ParseInt(s[i+1:], 10, 32); err != nil {
+			return Decimal{}, invalid
+		}
+		mantissa = s[:i]
+	}
+	sign := ""
+	if strings.HasPrefix(mantissa, "-") || strings.HasPrefix(mantissa, "+") {
+		sign, mantissa = mantissa[:1], mantissa[1:]
+	}
+	whole, fraction, _ := strings.Cut(mantissa, ".")
+	digits := whole + fraction
+	if digits == "" || strings.Trim(digits, "0123456789") != "" {
+		return Decimal{}, invalid
+	}
+	value, _ := new(big.Int).SetString(sign+digits, 10)
+	scale := int64(len(fraction)) - exp
+	if scale < -maxDecimalDigits || scale > maxDecimalDigits {
+		return Decimal{}, invalid
+	}
+	if scale < 0 {
+		value.Mul(value, pow10(-scale))
+		scale = 0
+	}
+	return Decimal{value: value, scale: int32(scale)}, nil
+}
+
+// MustParseDecimal is like ParseDecimal but panics if s can not be parsed.
+func MustParseDecimal(s string) Decimal {
+	d, err := ParseDecimal(s)
+	if err != nil {
+		panic(err)
+	}
+	return d
+}
+
+// pow10 returns 10^n.
+func pow10(n int64) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(n), nil)
+}
+
+// unscaled returns the unscaled integer, it must not be modified.
+func (d Decimal) unscaled() *big.Int {
+	if d.value == nil {
+		return new(big.Int)
+	}
+	return d.value
+}
+
+// rescaled returns the unscaled integer of d with the larger scale.
+func (d Decimal) rescaled(scale int32) *big.Int {
+	return new(big.Int).Mul(d.unscaled(), pow10(int64(scale-d.scale)))
+}
+
+// Scale returns the number of digits after the point.
+func (d Decimal) Scale() int32 {
+	return d.scale
+}
+
+// Unscaled returns the integer which is d * 10^scale.
+func (d Decimal) Unscaled() *big.Int {
+	return new(big.Int).Set(d.unscaled())
+}
+
+// Sign returns -1, 0 or 1 for negative, zero or positive d.
+func (d Decimal) Sign() int {
+	return d.unscaled().Sign()
+}
+
+// IsZero reports whether d is zero.
+func (d Decimal) IsZero() bool {
+	return d.Sign() == 0
+}
+
+// Cmp returns -1, 0 or 1 if d is less than, equal to or greater than other.
+// The scale does not matter: 1.50 is equal to 1.5.
+func (d Decimal) Cmp(other Decimal) int {
+	scale := max(d.scale, other.scale)
+	return d.rescaled(scale).Cmp(other.rescaled(scale))
+}
+
+// Equal reports whether d and other are the same number.
+func (d Decimal) Equal(other Decimal) bool {
+	return d.Cmp(other) == 0
+}
+
+// Neg returns -d.
+func (d Decimal) Neg() Decimal {
+	return Decimal{value: new(big.Int).Neg(d.unscaled()), scale: d.scale}
+}
+
+// Abs returns |d|.
+func (d Decimal) Abs() Decimal {
+	return Decimal{value: new(big.Int).Abs(d.unscaled()), scale: d.scale}
+}
+
+// Add returns d + other with the larger of the scales.
+func (d Decimal) Add(other Decimal) Decimal {
+	scale := max(d.scale, other.scale)
+	return Decimal{value: new(big.Int).Add(d.rescaled(scale), other.rescaled(scale)), scale: scale}
+}
+
+// Sub returns d - other with the larger of the scales.
+func (d Decimal) Sub(other Decimal) Decimal {
+	return d.Add(other.Neg())
+}
+
+// Mul returns d * other with the sum of the scales, so the product is exact.
+// It panics if the sum of the scales overflows int32 like the negative scale does.
+func (d Decimal) Mul(other Decimal) Decimal {
+	scale, ok := Add(d.scale, other.scale)
+	if !ok {
+		panic("mathutils: scale overflow")
+	}
+	return Decimal{value: new(big.Int).Mul(d.unscaled(), other.unscaled()), scale: scale}
+}
+
+// Div returns d / other rounded to the scale by the mode.
+func (d Decimal) Div(other Decimal, scale int32, mode RoundingMode) (Decimal, error) {
+	checkScale(scale)
+	if other.IsZero() {
+		return Decimal{}, ErrDivisionByZero
+	}
+	// d / other = (a * 10^-p) / (b * 10^-q), the result is a * 10^(scale+q-p) / b * 10^-scale
+	num, den := new(big.Int).Set(d.unscaled()), new(big.Int).Set(other.unscaled())
+	if exp := int64(scale) + int64(other.scale) - int64(d.scale); exp >= 0 {
+		num.Mul(num, pow10(exp))
+	} else {
+		den.Mul(den, pow10(-exp))
+	}
+	return Decimal{value: roundQuo(num, den, mode), scale: scale}, nil
+}
+
+// Round returns d rounded to the scale by the mode, the larger scale just appends zeros.
+func (d Decimal) Round(scale int32, mode RoundingMode) Decimal {
+	checkScale(scale)
+	if scale >= d.scale {
+		return Decimal{value: d.rescaled(scale), scale: scale}
+	}
+	return Decimal{value: roundQuo(d.unscaled(), pow10(int64(d.scale-scale)), mode), scale: scale}
+}
+
+// roundQuo returns num / den rounded to the integer by the mode.
+func roundQuo(num, den *big.Int, mode RoundingMode) *big.Int {
+	q, r := new(big.Int).QuoRem(num, den, new(big.Int))
+	if r.Sign() == 0 {
+		return q
+	}
+	sign := num.Sign() * den.Sign()
+	// half is the comparison of the remainder with the half of the divisor
+	half := new(big.Int).Lsh(r.Abs(r), 1).Cmp(new(big.Int).Abs(den))
+	var away bool
+	switch mode {
+	case RoundHalfUp:
+		away = half >= 0
+	case RoundHalfEven:
+		away = half > 0 || half == 0 && q.Bit(0) == 1
+	case RoundFloor:
+		away = sign < 0
+	case RoundCeil:
+		away = sign > 0
+	case RoundDown:
+		away = false
+	default:
+		panic("mathutils: unknown rounding mode")
+	}
+	if away {
+		q.Add(q, big.NewInt(int64(sign)))
+	}
+	return q
+}
+
+// Int64 returns the integer part of d and false if it does not fit into int64.
+func (d Decimal) Int64() (int64, bool) {
+	n := d.Round(0, RoundDown).unscaled()
+	if !n.IsInt64() {
+		return 0, false
+	}
+	return n.Int64(), true
+}
+
+// Float64 returns the nearest float64 of d.
+func (d Decimal) Float64() float64 {
+	f, _ := strconv.ParseFloat(d.String(), 64)
+	return f
+}
+
+// String returns d with all digits of the scale, e.g. "-123.450".
+func (d Decimal) String() string {
+	digits := new(big.Int).Abs(d.unscaled()).String()
+	if d.scale > 0 {
+		if pad := int(d.scale) + 1 - len(digits); pad > 0 {
+			digits = strings.Repeat("0", pad) + digits
+		}
+		digits = digits[:len(digits)-int(d.scale)] + "." + digits[len(digits)-int(d.scale):]
+	}
+	if d.Sign() < 0 {
+		return "-" + digits
+	}
+	return digits
+}
+
+// MarshalText encodes d like String, so JSON encodes it as a string without loss of digits.
+func (d Decimal) MarshalText() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+// UnmarshalText decodes d from the text accepted by ParseDecimal.
+func (d *Decimal) UnmarshalText(text []byte) error {
+	v, err := ParseDecimal(string(text))
+	if err != nil {
+		return err
+	}
+	*d = v
+	return nil
+}
+
+// UnmarshalJSON decodes d from JSON string or number, null keeps d unchanged.
+func (d *Decimal) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(data, []byte("null")) {
+		return nil
+	}
+	if s, err := strconv.Unquote(string(data)); err == nil {
+		data = []byte(s)
+	}
+	return d.UnmarshalText(data)
+}
+
+// Scan implements sql.Scanner, so NUMERIC columns can be scanned into Decimal.
+func (d *Decimal) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case []byte:
+		return d.UnmarshalText(v)
+	case string:
+		return d.UnmarshalText([]byte(v))
+	case int64:
+		*d = NewDecimal(v, 0)
+		return nil
+	case float64:
+		return d.UnmarshalText([]byte(strconv.FormatFloat(v, 'f', -1, 64)))
+	case nil:
+		return errors.New("mathutils: can not scan NULL into Decimal, use NullDecimal")
+	}
+	return fmt.Errorf("mathutils: can not scan %T into Decimal", src)
+}
+
+// Value implements driver.Valuer, the value is the text of d.
+func (d Decimal) Value() (driver.Value, error) {
+	return d.String(), nil
+}
+
+// NullDecimal is Decimal which may be NULL in the database, like sql.NullInt64.
+type NullDecimal struct {
+	Decimal Decimal
+	// Valid is true if Decimal is not NULL
+	Valid bool
+}
+
+// Scan implements sql.Scanner.
+func (n *NullDecimal) Scan(src interface{}) error {
+	if src == nil {
+		n.Decimal, n.Valid = Decimal{}, false
+		return nil
+	}
+	n.Valid = true
+	return n.Decimal.Scan(src)
+}
+
+// Value implements driver.Valuer.
+func (n NullDecimal) Value() (driver.Value, error) {
+	if !n.Valid {
+		return nil, nil
+	}
+	return n.Decimal.Value()
+}
@@ -0,0 +1,192 @@
+package mathutils
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"math"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseDecimal(t *testing.T) {
+	cases := []struct {
+		input    string
+		expected string
+		scale    int32
+	}{
+		{input: "0", expected: "0", scale: 0},
+		{input: "-123.450", expected: "-123.450", scale: 3},
+		{input: "+7", expected: "7", scale: 0},
+		{input: ".5", expected: "0.5", scale: 1},
+		{input: "5.", expected: "5", scale: 0},
+		{input: "-0.001", expected: "-0.001", scale: 3},
+		{input: "1.5e-3", expected: "0.0015", scale: 4},
+		{input: "1.5E3", expected: "1500", scale: 0},
+		{input: "12.345e1", expected: "123.45", scale: 2},
+		{input: "123456789012345678901234567890.12", expected: "123456789012345678901234567890.12", scale: 2},
+	}
+	for _, c := range cases {
+		d, err := ParseDecimal(c.input)
+		assert.NoError(t, err, c.input)
+		assert.Equal(t, c.expected, d.String(), c.input)
+		assert.Equal(t, c.scale, d.Scale(), c.input)
+	}
+	for _, input := range []string{"", "-", ".", "1.2.3", "--1", "+-1", "1e", "e5", "1,5", "0x10", "NaN", " 1", "1e999999"} {
+		_, err := ParseDecimal(input)
+		assert.Error(t, err, input)
+	}
+	assert.Panics(t, func() { MustParseDecimal("x") })
+	assert.Panics(t, func() { NewDecimal(1, -1) })
+}
+
+func TestDecimalArithmetic(t *testing.T) {
+	a, b := MustParseDecimal("10.25"), MustParseDecimal("-3.5")
+	assert.Equal(t, "6.75", a.Add(b).String())
+	assert.Equal(t, "13.75", a.Sub(b).String())
+	assert.Equal(t, "-35.875", a.Mul(b).String())
+	assert.Equal(t, "-3.5", b.String())
+	assert.Equal(t, "3.5", b.Abs().String())
+	assert.Equal(t, "-10.25", a.Neg().String())
+	// 0.1 + 0.2 is exactly 0.3 unlike float64
+	assert.True(t, MustParseDecimal("0.1").Add(MustParseDecimal("0.2")).Equal(MustParseDecimal("0.3")))
+
+	q, err := a.Div(b, 4, RoundHalfEven)
+	assert.NoError(t, err)
+	assert.Equal(t, "-2.9286", q.String())
+	q, err = NewDecimal(1, 0).Div(NewDecimal(3, 0), 2, RoundDown)
+	assert.NoError(t, err)
+	assert.Equal(t, "0.33", q.String())
+	q, err = NewDecimal(12345, 4).Div(NewDecimal(5, 0), 0, RoundHalfUp)
+	assert.NoError(t, err)
+	assert.Equal(t, "0", q.String())
+	_, err = a.Div(Decimal{}, 2, RoundHalfUp)
+	assert.Equal(t, ErrDivisionByZero, err)
+
+	var zero Decimal
+	assert.True(t, zero.IsZero())
+	assert.Equal(t, "0", zero.String())
+	assert.Equal(t, "10.25", zero.Add(a).String())
+	assert.Equal(t, 0, NewDecimal(150, 2).Cmp(NewDecimal(15, 1)))
+	assert.Equal(t, -1, b.Cmp(a))
+	assert.Equal(t, 1, a.Cmp(zero))
+	assert.Equal(t, -1, b.Sign())
+	assert.Equal(t, big.NewInt(1025), a.Unscaled())
+	assert.Equal(t, "12345.67", NewDecimalFromBig(big.NewInt(1234567), 2).String())
+
+	huge := NewDecimal(1, math.MaxInt32/2+1)
+	assert.Equal(t, int32(math.MaxInt32-1), NewDecimal(1, math.MaxInt32/2).Mul(NewDecimal(1, math.MaxInt32/2)).Scale())
+	assert.Panics(t, func() { huge.Mul(huge) })
+}
+
+func TestDecimalRound(t *testing.T) {
+	cases := []struct {
+		input    string
+		scale    int32
+		mode     RoundingMode
+		expected string
+	}{
+		{input: "2.5", scale: 0, mode: RoundHalfUp, expected: "3"},
+		{input: "-2.5", scale: 0, mode: RoundHalfUp, expected: "-3"},
+		{input: "2.49", scale: 0, mode: RoundHalfUp, expected: "2"},
+		{input: "2.5", scale: 0, mode: RoundHalfEven, expected: "2"},
+		{input: "3.5", scale: 0, mode: RoundHalfEven, expected: "4"},
+		{input: "-2.5", scale: 0, mode: RoundHalfEven, expected: "-2"},
+		{input: "-3.5", scale: 0, mode: RoundHalfEven, expected: "-4"},
+		{input: "2.51", scale: 0, mode: RoundHalfEven, expected: "3"},
+		{input: "2.7", scale: 0, mode: RoundFloor, expected: "2"},
+		{input: "-2.1", scale: 0, mode: RoundFloor, expected: "-3"},
+		{input: "2.1", scale: 0, mode: RoundCeil, expected: "3"},
+		{input: "-2.7", scale: 0, mode: RoundCeil, expected: "-2"},
+		{input: "2.7", scale: 0, mode: RoundDown, expected: "2"},
+		{input: "-2.7", scale: 0, mode: RoundDown, expected: "-2"},
+		{input: "1.005", scale: 2, mode: RoundHalfUp, expected: "1.01"},
+		{input: "1.005", scale: 2, mode: RoundHalfEven, expected: "1.00"},
+		{input: "-0.0049", scale: 2, mode: RoundHalfUp, expected: "0.00"},
+		{input: "-0.001", scale: 2, mode: RoundFloor, expected: "-0.01"},
+		{input: "1.5", scale: 3, mode: RoundHalfUp, expected: "1.500"},
+		{input: "7", scale: 0, mode: RoundCeil, expected: "7"},
+	}
+	for _, c := range cases {
+		d := MustParseDecimal(c.input).Round(c.scale, c.mode)
+		assert.Equal(t, c.expected, d.String(), c.input, c.mode)
+		assert.Equal(t, c.scale, d.Scale(), c.input, c.mode)
+	}
+	assert.Panics(t, func() { NewDecimal(1, 1).Round(0, RoundingMode(42)) })
+}
+
+func TestDecimalConversions(t *testing.T) {
+	n, ok := MustParseDecimal("-123.99").Int64()
+	assert.True(t, ok)
+	assert.Equal(t, int64(-123), n)
+	_, ok = MustParseDecimal("9223372036854775808").Int64()
+	assert.False(t, ok)
+	n, ok = MustParseDecimal("-9223372036854775808.5").Int64()
+	assert.True(t, ok)
+	assert.Equal(t, int64(-9223372036854775808), n)
+	assert.Equal(t, 0.1, MustParseDecimal("0.1").Float64())
+	assert.Equal(t, -1234.5, MustParseDecimal("-1234.50").Float64())
+}
+
+func TestDecimalJSON(t *testing.T) {
+	type order struct {
+		Total Decimal  `json:"total"`
+		Fee   *Decimal `json:"fee"`
+	}
+	data, err := json.Marshal(order{Total: MustParseDecimal("19.90")})
+	assert.NoError(t, err)
+	assert.Equal(t, `{"total":"19.90","fee":null}`, string(data))
+
+	var o order
+	assert.NoError(t, json.Unmarshal([]byte(`{"total":19.90,"fee":"0.05"}`), &o))
+	assert.Equal(t, "19.90", o.Total.String())
+	assert.Equal(t, "0.05", o.Fee.String())
+	assert.NoError(t, json.Unmarshal([]byte(`{"total":null}`), &o))
+	assert.Equal(t, "19.90", o.Total.String())
+	assert.Error(t, json.Unmarshal([]byte(`{"total":"abc"}`), &o))
+	assert.Error(t, json.Unmarshal([]byte(`{"total":true}`), &o))
+}
+
+func TestDecimalSQL(t *testing.T) {
+	var (
+		_ sql.Scanner   = &Decimal{}
+		_ driver.Valuer = Decimal{}
+		_ sql.Scanner   = &NullDecimal{}
+		_ driver.Valuer = NullDecimal{}
+	)
+	cases := []struct {
+		src      interface{}
+		expected string
+	}{
+		{src: []byte("12345.6789"), expected: "12345.6789"},
+		{src: "-0.50", expected: "-0.50"},
+		{src: int64(42), expected: "42"},
+		{src: 2.25, expected: "2.25"},
+	}
+	for _, c := range cases {
+		var d Decimal
+		assert.NoError(t, d.Scan(c.src), c.src)
+		assert.Equal(t, c.expected, d.String(), c.src)
+	}
+	var d Decimal
+	assert.Error(t, d.Scan(nil))
+	assert.Error(t, d.Scan(true))
+	v, err := MustParseDecimal("1.10").Value()
+	assert.NoError(t, err)
+	assert.Equal(t, "1.10", v)
+
+	var n NullDecimal
+	assert.NoError(t, n.Scan(nil))
+	assert.False(t, n.Valid)
+	v, err = n.Value()
+	assert.NoError(t, err)
+	assert.Nil(t, v)
+	assert.NoError(t, n.Scan([]byte("3.14")))
+	assert.True(t, n.Valid)
+	assert.Equal(t, "3.14", n.Decimal.String())
+	v, err = n.Value()
+	assert.NoError(t, err)
+	assert.Equal(t, "3.14", v)
+}
@@ -0,0 +1,180 @@
+package mathutils
+
+import (
+	"errors"
+	"math"
+	"math/big"
+)
+
+var (
+	// ErrOverflow is returned when the digits do not fit into the integer type.
+	ErrOverflow = errors.New("mathutils: value out of range")
+	// ErrInvalidDigit is returned when a digit is out of the base or the signs of digits differ.
+	ErrInvalidDigit = errors.New("mathutils: invalid digit")
+)
+
+// checkBase panics if base is not in range 2..36 like strconv.FormatInt does.
+func checkBase(base int) {
+	if base < 2 || base > 36 {
+		panic("mathutils: illegal base")
+	}
+}
+
+// abs returns the magnitude of number, it is exact for math.MinInt64
+func abs(number int64) uint64 {
+	if number < 0 {
+		return uint64(-(number + 1)) + 1
+	}
+	return uint64(number)
+}
+
+// CountOfDigitsBase returns count of digits in number in base 2..36
+func CountOfDigitsBase(number int64, base int) int {
+	return CountOfUintDigits(abs(number), base)
+}
+
+// CountOfUintDigits returns count of digits in number in base 2..36
+func CountOfUintDigits(number uint64, base int) int {
+	checkBase(base)
+	count := 1
+	for b := uint64(base); number >= b; number /= b {
+		count++
+	}
+	return count
+}
+
+// CountOfBigDigits returns count of digits in number in base 2..36
+func CountOfBigDigits(number *big.Int, base int) int {
+	checkBase(base)
+	return len(new(big.Int).Abs(number).Text(base))
+}
+
+// IntToDigitsBase returns array of digits from number in base 2..36,
+// the digits of negative number are negative
+func IntToDigitsBase(number int64, base int) []int {
+	digits := UintToDigits(abs(number), base)
+	if number < 0 {
+		for i := range digits {
+			digits[i] = -digits[i]
+		}
+	}
+	return digits
+}
+
+// UintToDigits returns array of digits from number in base 2..36
+func UintToDigits(number uint64, base int) []int {
+	digits := make([]int, CountOfUintDigits(number, base))
+	for i, b := len(digits)-1, uint64(base); i >= 0; i-- {
+		digits[i] = int(number % b)
+		number /= b
+	}
+	return digits
+}
+
+// BigToDigits returns array of digits from number in base 2..36,
+// the digits of negative number are negative
+func BigToDigits(number *big.Int, base int) []int {
+	checkBase(base)
+	text := new(big.Int).Abs(number).Text(base)
+	sign := number.Sign()
+	digits := make([]int, len(text))
+	for i := 0; i < len(text); i++ {
+		c := text[i]
+		if c <= '9' {
+			digits[i] = int(c - '0')
+		} else {
+			digits[i] = int(c-'a') + 10
+		}
+		if sign < 0 {
+			digits[i] = -digits[i]
+		}
+	}
+	return digits
+}
+
+// digitsSign returns -1 if the digits are negative, 1 otherwise,
+// and checks that every digit fits into base and all digits have the same sign.
+func digitsSign(digits []int, base int) (int, error) {
+	checkBase(base)
+	sign := 1
+	for _, d := range digits {
+		if d < 0 {
+			sign = -1
+			break
+		}
+	}
+	for _, d := range digits {
+		if d*sign < 0 || d*sign >= base {
+			return 0, ErrInvalidDigit
+		}
+	}
+	return sign, nil
+}
+
+// DigitsToInt returns the number from array of digits in base 2..36,
+// it is the inverse of IntToDigitsBase. It returns ErrOverflow if the number
+// does not fit into int64.
+func DigitsToInt(digits []int, base int) (int64, error) {
+	sign, err := digitsSign(digits, base)
+	if err != nil {
+		return 0, err
+	}
+	limit := uint64(math.MaxInt64)
+	if sign < 0 {
+		limit++
+	}
+	magnitude, err := digitsToUint(digits, base, sign, limit)
+	if err != nil {
+		return 0, err
+	}
+	if sign < 0 {
+		return -int64(magnitude-1) - 1, nil
+	}
+	return int64(magnitude), nil
+}
+
+// DigitsToUint returns the number from array of digits in base 2..36,
+// it is the inverse of UintToDigits. It returns ErrOverflow if the number
+// does not fit into uint64.
+func DigitsToUint(digits []int, base int) (uint64, error) {
+	sign, err := digitsSign(digits, base)
+	if err != nil {
+		return 0, err
+	}
+	if sign < 0 {
+		return 0, ErrInvalidDigit
+	}
+	return digitsToUint(digits, base, sign, math.MaxUint64)
+}
+
+func digitsToUint(digits []int, base, sign int, limit uint64) (uint64, error) {
+	var number uint64
+	b := uint64(base)
+	for _, d := range digits {
+		digit := uint64(d * sign)
+		if number > (limit-digit)/b {
+			return 0, ErrOverflow
+		}
+		number = number*b + digit
+	}
+	return number, nil
+}
+
+// DigitsToBig returns the number from array of digits in base 2..36,
+// it is the inverse of BigToDigits.
+func DigitsToBig(digits []int, base int) (*big.Int, error) {
+	sign, err := digitsSign(digits, base)
+	if err != nil {
+		return nil, err
+	}
+	number := new(big.Int)
+	b := big.NewInt(int64(base))
+	for _, d := range digits {
+		number.Mul(number, b)
+		number.Add(number, big.NewInt(int64(d*sign)))
+	}
+	if sign < 0 {
+		number.Neg(number)
+	}
+	return number, nil
+}
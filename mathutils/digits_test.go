@@ -0,0 +1,193 @@
+package mathutils
+
+import (
+	"math"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCountOfDigitsBase(t *testing.T) {
+	cases := []struct {
+		input    int64
+		base     int
+		expected int
+	}{
+		{
+			input:    0,
+			base:     2,
+			expected: 1,
+		},
+		{
+			input:    999,
+			base:     10,
+			expected: 3,
+		},
+		{
+			input:    1000,
+			base:     10,
+			expected: 4,
+		},
+		{
+			input:    999999999999999999,
+			base:     10,
+			expected: 18,
+		},
+		{
+			input:    1000000000000000000,
+			base:     10,
+			expected: 19,
+		},
+		{
+			input:    math.MaxInt64,
+			base:     10,
+			expected: 19,
+		},
+		{
+			input:    math.MinInt64,
+			base:     10,
+			expected: 19,
+		},
+		{
+			input:    math.MinInt64,
+			base:     2,
+			expected: 64,
+		},
+		{
+			input:    255,
+			base:     16,
+			expected: 2,
+		},
+		{
+			input:    -36,
+			base:     36,
+			expected: 2,
+		},
+	}
+	for _, c := range cases {
+		assert.Equal(t, c.expected, CountOfDigitsBase(c.input, c.base), c.input)
+		assert.Equal(t, c.expected, CountOfBigDigits(big.NewInt(c.input), c.base), c.input)
+	}
+	assert.Equal(t, 20, CountOfUintDigits(math.MaxUint64, 10))
+	assert.Equal(t, 16, CountOfUintDigits(math.MaxUint64, 16))
+	assert.Equal(t, 19, CountOfDigits(math.MinInt64))
+	assert.Panics(t, func() { CountOfDigitsBase(1, 1) })
+	assert.Panics(t, func() { CountOfUintDigits(1, 37) })
+}
+
+func TestDigitsBase(t *testing.T) {
+	cases := []struct {
+		input    int64
+		base     int
+		expected []int
+	}{
+		{
+			input:    0,
+			base:     10,
+			expected: []int{0},
+		},
+		{
+			input:    5,
+			base:     2,
+			expected: []int{1, 0, 1},
+		},
+		{
+			input:    -255,
+			base:     16,
+			expected: []int{-15, -15},
+		},
+		{
+			input:    1295,
+			base:     36,
+			expected: []int{35, 35},
+		},
+		{
+			input:    math.MaxInt64,
+			base:     10,
+			expected: []int{9, 2, 2, 3, 3, 7, 2, 0, 3, 6, 8, 5, 4, 7, 7, 5, 8, 0, 7},
+		},
+		{
+			input:    math.MinInt64,
+			base:     10,
+			expected: []int{-9, -2, -2, -3, -3, -7, -2, 0, -3, -6, -8, -5, -4, -7, -7, -5, -8, 0, -8},
+		},
+	}
+	for _, c := range cases {
+		assert.Equal(t, c.expected, IntToDigitsBase(c.input, c.base), c.input)
+		assert.Equal(t, c.expected, BigToDigits(big.NewInt(c.input), c.base), c.input)
+		number, err := DigitsToInt(c.expected, c.base)
+		assert.NoError(t, err, c.input)
+		assert.Equal(t, c.input, number)
+		bn, err := DigitsToBig(c.expected, c.base)
+		assert.NoError(t, err, c.input)
+		assert.Equal(t, 0, bn.Cmp(big.NewInt(c.input)), c.input)
+	}
+	assert.Equal(t, []int{15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15},
+		UintToDigits(math.MaxUint64, 16))
+}
+
+func TestDigitsToInt(t *testing.T) {
+	cases := []struct {
+		digits   []int
+		base     int
+		expected int64
+		err      error
+	}{
+		{
+			digits:   nil,
+			base:     10,
+			expected: 0,
+		},
+		{
+			digits:   []int{0, 0, 4, 2},
+			base:     10,
+			expected: 42,
+		},
+		{
+			digits: []int{9, 2, 2, 3, 3, 7, 2, 0, 3, 6, 8, 5, 4, 7, 7, 5, 8, 0, 8},
+			base:   10,
+			err:    ErrOverflow,
+		},
+		{
+			digits: []int{-9, -2, -2, -3, -3, -7, -2, 0, -3, -6, -8, -5, -4, -7, -7, -5, -8, 0, -9},
+			base:   10,
+			err:    ErrOverflow,
+		},
+		{
+			digits: []int{1, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0},
+			base:   10,
+			err:    ErrOverflow,
+		},
+		{
+			digits: []int{1, 10},
+			base:   10,
+			err:    ErrInvalidDigit,
+		},
+		{
+			digits: []int{1, -1},
+			base:   10,
+			err:    ErrInvalidDigit,
+		},
+	}
+	for _, c := range cases {
+		number, err := DigitsToInt(c.digits, c.base)
+		assert.Equal(t, c.err, err, c.digits)
+		assert.Equal(t, c.expected, number, c.digits)
+	}
+
+	n, err := DigitsToUint(UintToDigits(math.MaxUint64, 7), 7)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(math.MaxUint64), n)
+	_, err = DigitsToUint(append(UintToDigits(math.MaxUint64, 7), 0), 7)
+	assert.Equal(t, ErrOverflow, err)
+	_, err = DigitsToUint([]int{-1}, 10)
+	assert.Equal(t, ErrInvalidDigit, err)
+
+	big20, _ := new(big.Int).SetString("-100000000000000000000", 10)
+	bn, err := DigitsToBig(BigToDigits(big20, 10), 10)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, bn.Cmp(big20))
+	_, err = DigitsToBig([]int{2}, 2)
+	assert.Equal(t, ErrInvalidDigit, err)
+}
@@ -0,0 +1,271 @@
+package mathutils
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Units defines the system of units of bytes.
+type Units int
+
+const (
+	// IEC units are powers of 1024: KiB, MiB, GiB, ...
+	IEC Units = iota
+	// SI units are powers of 1000: kB, MB, GB, ...
+	SI
+)
+
+var (
+	iecUnits   = []string{"B", "KiB", "MiB", "GiB", "TiB", "PiB", "EiB"}
+	siUnits    = []string{"B", "kB", "MB", "GB", "TB", "PB", "EB"}
+	countUnits = []string{"", "k", "M", "G", "T", "P", "E"}
+)
+
+// FormatBytes returns human readable bytes count in the units with precision digits
+// after the point, the precision -1 uses the minimal number of digits.
+func FormatBytes(bytes uint64, units Units, precision int) string {
+	names, base := iecUnits, 1024.0
+	if units == SI {
+		names, base = siUnits, 1000.0
+	}
+	if bytes < uint64(base) {
+		return strconv.FormatUint(bytes, 10) + " B"
+	}
+	value, exp := scale(float64(bytes), base, precision, len(names)-1)
+	return value + " " + names[exp]
+}
+
+// ParseHumanBytes parses bytes count like "1.5 GiB", "300MB", "2e6" or "512k".
+// The units are case-insensitive, "iB" units are powers of 1024 and "B" units are powers of 1000,
+// the letter "B" may be omitted. The integer counts are exact up to math.MaxUint64.
+func ParseHumanBytes(s string) (uint64, error) {
+	number, unit := splitUnit(s)
+	var multiplier uint64 = 1
+	if unit != "" && !strings.EqualFold(unit, "B") {
+		u := strings.ToUpper(strings.TrimSuffix(strings.TrimSuffix(unit, "B"), "b"))
+		var base uint64 = 1000
+		if strings.HasSuffix(u, "I") {
+			u, base = strings.TrimSuffix(u, "I"), 1024
+		}
+		exp := unitIndex(u)
+		if exp <= 0 {
+			return 0, fmt.Errorf("mathutils: invalid bytes count %q", s)
+		}
+		multiplier, _ = Pow(base, uint(exp))
+	}
+	if n, err := strconv.ParseUint(number, 10, 64); err == nil {
+		if bytes, ok := Mul(n, multiplier); ok {
+			return bytes, nil
+		}
+		return 0, ErrOverflow
+	}
+	value, err := strconv.ParseFloat(number, 64)
+	if err != nil || value < 0 {
+		return 0, fmt.Errorf("mathutils: invalid bytes count %q", s)
+	}
+	value = math.Round(value * float64(multiplier))
+	if value >= math.MaxUint64 {
+		return 0, ErrOverflow
+	}
+	return uint64(value), nil
+}
+
+// HumanCount returns human readable count like "1.2k" or "3.4M".
+func HumanCount(n int64) string {
+	return FormatCount(n, 1)
+}
+
+// FormatCount returns human readable count with precision digits after the point,
+// the precision -1 uses the minimal number of digits.
+func FormatCount(n int64, precision int) string {
+	sign := ""
+	if n < 0 {
+		sign = "-"
+	}
+	magnitude := abs(n)
+	if magnitude < 1000 {
+		return sign + strconv.FormatUint(magnitude, 10)
+	}
+	value, exp := scale(float64(magnitude), 1000, precision, len(countUnits)-1)
+	return sign + value + countUnits[exp]
+}
+
+// ParseHumanCount parses count like "1.2k", "3.4M", "-5" or "2e6",
+// the suffixes k, M, G, T, P and E are case-insensitive.
+func ParseHumanCount(s string) (int64, error) {
+	number, unit := splitUnit(s)
+	exp := unitIndex(strings.ToUpper(unit))
+	if exp < 0 {
+		return 0, fmt.Errorf("mathutils: invalid count %q", s)
+	}
+	value, err := strconv.ParseFloat(number, 64)
+	if err != nil {
+		return 0, fmt.Errorf("mathutils: invalid count %q", s)
+	}
+	value = math.Round(value * math.Pow(1000, float64(exp)))
+	if value >= math.MaxInt64 || value < math.MinInt64 {
+		return 0, ErrOverflow
+	}
+	return int64(value), nil
+}
+
+// HumanDuration returns human readable duration with days like "3d4h" or "1h30m15.5s",
+// the durations shorter than a second are formatted like time.Duration.String.
+func HumanDuration(d time.Duration) string {
+	sign := ""
+	if d < 0 {
+		sign = "-"
+	}
+	magnitude := abs(int64(d))
+	if magnitude < uint64(time.Second) {
+		return sign + time.Duration(magnitude).String()
+	}
+	var b strings.Builder
+	b.WriteString(sign)
+	for _, u := range []struct {
+		name string
+		size time.Duration
+	}{
+		{"d", 24 * time.Hour},
+		{"h", time.Hour},
+		{"m", time.Minute},
+	} {
+		if n := magnitude / uint64(u.size); n > 0 {
+			b.WriteString(strconv.FormatUint(n, 10) + u.name)
+			magnitude %= uint64(u.size)
+		}
+	}
+	if magnitude > 0 {
+		b.WriteString(strconv.FormatUint(magnitude/uint64(time.Second), 10))
+		if nanos := magnitude % uint64(time.Second); nanos > 0 {
+			b.WriteString(strings.TrimRight(fmt.Sprintf(".%09d", nanos), "0"))
+		}
+		b.WriteString("s")
+	}
+	return b.String()
+}
+
+// ParseHumanDuration parses duration like time.ParseDuration
+// with additional units "d" (24 hours) and "w" (7 days), e.g. "3d4h" or "1w".
+func ParseHumanDuration(s string) (time.Duration, error) {
+	invalid := fmt.Errorf("mathutils: invalid duration %q", s)
+	rest, sign := s, ""
+	if strings.HasPrefix(rest, "-") || strings.HasPrefix(rest, "+") {
+		rest, sign = rest[1:], rest[:1]
+	}
+	if rest == "" {
+		return 0, invalid
+	}
+	var (
+		days  time.Duration
+		other strings.Builder
+	)
+	for rest != "" {
+		i := strings.IndexFunc(rest, func(c rune) bool {
+			return (c < '0' || c > '9') && c != '.'
+		})
+		if i <= 0 {
+			return 0, invalid
+		}
+		j := strings.IndexFunc(rest[i:], func(c rune) bool {
+			return c >= '0' && c <= '9' || c == '.'
+		})
+		if j < 0 {
+			j = len(rest) - i
+		}
+		number, unit := rest[:i], rest[i:i+j]
+		rest = rest[i+j:]
+		if unit != "d" && unit != "w" {
+			other.WriteString(number + unit)
+			continue
+		}
+		size := 24 * time.Hour
+		if unit == "w" {
+			size *= 7
+		}
+		// the whole units are counted exactly, only the fraction is rounded
+		whole, fraction, _ := strings.Cut(number, ".")
+		n, err := strconv.ParseInt(sign+"0"+whole, 10, 64)
+		if err != nil {
+			return 0, ErrOverflow
+		}
+		f, err := strconv.ParseFloat("0."+fraction+"0", 64)
+		if err != nil {
+			return 0, invalid
+		}
+		if n > math.MaxInt64/int64(size) || n < math.MinInt64/int64(size) {
+			return 0, ErrOverflow
+		}
+		if sign == "-" {
+			f = -f
+		}
+		part, ok := addDurations(time.Duration(n)*size, time.Duration(math.Round(f*float64(size))))
+		if ok {
+			days, ok = addDurations(days, part)
+		}
+		if !ok {
+			return 0, ErrOverflow
+		}
+	}
+	var d time.Duration
+	if other.Len() > 0 {
+		var err error
+		if d, err = time.ParseDuration(sign + other.String()); err != nil {
+			return 0, invalid
+		}
+	}
+	total, ok := addDurations(days, d)
+	if !ok {
+		return 0, ErrOverflow
+	}
+	return total, nil
+}
+
+// addDurations returns the sum of durations of the same sign and false on overflow.
+func addDurations(a, b time.Duration) (time.Duration, bool) {
+	sum := a + b
+	if a > 0 && b > 0 && sum < 0 || a < 0 && b < 0 && sum >= 0 {
+		return 0, false
+	}
+	return sum, true
+}
+
+// scale returns value divided by the largest power of base (up to maxExp) which keeps it not less than 1,
+// formatted with precision, and the exponent of the power.
+func scale(value, base float64, precision, maxExp int) (string, int) {
+	exp := 0
+	for exp < maxExp && value >= base {
+		value /= base
+		exp++
+	}
+	text := strconv.FormatFloat(value, 'f', precision, 64)
+	// rounding may give the base, e.g. 1023.999 KiB is 1024.00 KiB
+	if rounded, _ := strconv.ParseFloat(text, 64); rounded >= base && exp < maxExp {
+		text = strconv.FormatFloat(value/base, 'f', precision, 64)
+		exp++
+	}
+	return text, exp
+}
+
+// splitUnit splits s into the number and the trailing letters of the unit.
+func splitUnit(s string) (string, string) {
+	s = strings.TrimSpace(s)
+	i := len(s)
+	for i > 0 && (s[i-1] >= 'a' && s[i-1] <= 'z' || s[i-1] >= 'A' && s[i-1] <= 'Z') {
+		i--
+	}
+	return strings.TrimSpace(s[:i]), s[i:]
+}
+
+// unitIndex returns the exponent of the upper-case prefix of the unit or -1.
+func unitIndex(unit string) int {
+	for i, u := range countUnits {
+		if strings.ToUpper(u) == unit {
+			return i
+		}
+	}
+	return -1
+}
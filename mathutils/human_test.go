@@ -0,0 +1,182 @@
+package mathutils
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormatBytes(t *testing.T) {
+	cases := []struct {
+		input     uint64
+		units     Units
+		precision int
+		expected  string
+	}{
+		{
+			input:     1023,
+			units:     IEC,
+			precision: 2,
+			expected:  "1023 B",
+		},
+		{
+			input:     1536,
+			units:     IEC,
+			precision: 2,
+			expected:  "1.50 KiB",
+		},
+		{
+			input:     1536,
+			units:     SI,
+			precision: 1,
+			expected:  "1.5 kB",
+		},
+		{
+			input:     1048575,
+			units:     IEC,
+			precision: 2,
+			expected:  "1.00 MiB",
+		},
+		{
+			input:     300000000,
+			units:     SI,
+			precision: -1,
+			expected:  "300 MB",
+		},
+		{
+			input:     math.MaxUint64,
+			units:     IEC,
+			precision: 0,
+			expected:  "16 EiB",
+		},
+	}
+	for _, c := range cases {
+		assert.Equal(t, c.expected, FormatBytes(c.input, c.units, c.precision), c.expected)
+	}
+}
+
+func TestParseHumanBytes(t *testing.T) {
+	cases := []struct {
+		input    string
+		expected uint64
+		err      bool
+	}{
+		{input: "0", expected: 0},
+		{input: "1023 B", expected: 1023},
+		{input: "1.5 GiB", expected: 3 << 29},
+		{input: "1.5gib", expected: 3 << 29},
+		{input: "300MB", expected: 300000000},
+		{input: "2e6", expected: 2000000},
+		{input: "512k", expected: 512000},
+		{input: "512Ki", expected: 512 << 10},
+		{input: "1.50 KiB", expected: 1536},
+		{input: "15 EiB", expected: 15 << 60},
+		{input: "18446744073709551615", expected: math.MaxUint64},
+		{input: "18446744073709551616", err: true},
+		{input: "16 EiB", err: true},
+		{input: "-1", err: true},
+		{input: "1 XB", err: true},
+		{input: "GB", err: true},
+	}
+	for _, c := range cases {
+		n, err := ParseHumanBytes(c.input)
+		if c.err {
+			assert.Error(t, err, c.input)
+			continue
+		}
+		assert.NoError(t, err, c.input)
+		assert.Equal(t, c.expected, n, c.input)
+	}
+}
+
+func TestHumanCount(t *testing.T) {
+	cases := []struct {
+		input    int64
+		expected string
+	}{
+		{input: 0, expected: "0"},
+		{input: 999, expected: "999"},
+		{input: -999, expected: "-999"},
+		{input: 1000, expected: "1.0k"},
+		{input: 1234, expected: "1.2k"},
+		{input: 999950, expected: "1.0M"},
+		{input: 3400000, expected: "3.4M"},
+		{input: math.MinInt64, expected: "-9.2E"},
+	}
+	for _, c := range cases {
+		assert.Equal(t, c.expected, HumanCount(c.input), c.expected)
+	}
+	assert.Equal(t, "1.234k", FormatCount(1234, -1))
+
+	parsed := []struct {
+		input    string
+		expected int64
+		err      bool
+	}{
+		{input: "999", expected: 999},
+		{input: "1.2k", expected: 1200},
+		{input: "3.4M", expected: 3400000},
+		{input: "-5K", expected: -5000},
+		{input: "2e6", expected: 2000000},
+		{input: "9.3E", err: true},
+		{input: "1x", err: true},
+		{input: "k", err: true},
+	}
+	for _, c := range parsed {
+		n, err := ParseHumanCount(c.input)
+		if c.err {
+			assert.Error(t, err, c.input)
+			continue
+		}
+		assert.NoError(t, err, c.input)
+		assert.Equal(t, c.expected, n, c.input)
+	}
+}
+
+func TestHumanDuration(t *testing.T) {
+	cases := []struct {
+		input    time.Duration
+		expected string
+	}{
+		{input: 0, expected: "0s"},
+		{input: 150 * time.Millisecond, expected: "150ms"},
+		{input: 90 * time.Second, expected: "1m30s"},
+		{input: 76 * time.Hour, expected: "3d4h"},
+		{input: 24*time.Hour + 5*time.Second, expected: "1d5s"},
+		{input: time.Hour + 1500*time.Millisecond, expected: "1h1.5s"},
+		{input: -(49 * time.Hour), expected: "-2d1h"},
+		{input: math.MinInt64, expected: "-106751d23h47m16.854775808s"},
+	}
+	for _, c := range cases {
+		assert.Equal(t, c.expected, HumanDuration(c.input), c.expected)
+		d, err := ParseHumanDuration(c.expected)
+		assert.NoError(t, err, c.expected)
+		assert.Equal(t, c.input, d, c.expected)
+	}
+
+	parsed := []struct {
+		input    string
+		expected time.Duration
+		err      bool
+	}{
+		{input: "1w", expected: 7 * 24 * time.Hour},
+		{input: "1.5d", expected: 36 * time.Hour},
+		{input: "+2h30m", expected: 150 * time.Minute},
+		{input: "3d", expected: 72 * time.Hour},
+		{input: "", err: true},
+		{input: "d", err: true},
+		{input: "3x", err: true},
+		{input: "200000w", err: true},
+	}
+	for _, c := range parsed {
+		d, err := ParseHumanDuration(c.input)
+		if c.err {
+			assert.Error(t, err, c.input)
+			continue
+		}
+		assert.NoError(t, err, c.input)
+		assert.Equal(t, c.expected, d, c.input)
+	}
+}
@@ -0,0 +1,186 @@
+package mathutils
+
+import (
+	"fmt"
+	"math"
+	"sync"
+	"time"
+)
+
+// TickInterval is the interval of updates of the moving averages, like of Unix load averages.
+const TickInterval = 5 * time.Second
+
+// Clock tells the current time, it is replaced in tests.
+type Clock interface {
+	Now() time.Time
+}
+
+// SystemClock is the Clock based on the time package.
+var SystemClock Clock = systemClock{}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+// EWMA is the exponentially weighted moving average of the rate of events per second
+// over the window, e.g. the number of messages per second over the last minute.
+// The events are counted between ticks of TickInterval, the average is updated
+// on the first call after a tick, so no background goroutine is needed.
+// EWMA is safe for concurrent use.
+type EWMA struct {
+	mu        sync.Mutex
+	clock     Clock
+	alpha     float64
+	rate      float64
+	uncounted int64
+	ticked    bool
+	last      time.Time
+}
+
+// NewEWMA returns EWMA over the window, the clock is SystemClock if nil.
+func NewEWMA(window time.Duration, clock Clock) *EWMA {
+	if window <= 0 {
+		panic("mathutils: window must be positive")
+	}
+	if clock == nil {
+		clock = SystemClock
+	}
+	return &EWMA{
+		clock: clock,
+		alpha: 1 - math.Exp(-TickInterval.Seconds()/window.Seconds()),
+		last:  clock.Now(),
+	}
+}
+
+// Update counts n events.
+func (e *EWMA) Update(n int64) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.tick()
+	e.uncounted += n
+}
+
+// Rate returns the average number of events per second,
+// the events of the current tick are not counted yet.
+func (e *EWMA) Rate() float64 {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.tick()
+	return e.rate
+}
+
+// tick applies the ticks passed since the last one, it is called with the lock held.
+func (e *EWMA) tick() {
+	ticks := e.clock.Now().Sub(e.last) / TickInterval
+	if ticks <= 0 {
+		return
+	}
+	e.last = e.last.Add(ticks * TickInterval)
+	instant := float64(e.uncounted) / TickInterval.Seconds()
+	e.uncounted = 0
+	if e.ticked {
+		e.rate += e.alpha * (instant - e.rate)
+	} else {
+		// the first tick sets the rate, so it does not grow slowly from zero
+		e.rate, e.ticked = instant, true
+	}
+	// the ticks without events decay the rate
+	e.rate *= math.Pow(1-e.alpha, float64(ticks-1))
+}
+
+// Meter measures the rate of events: the mean rate since the start
+// and 1, 5 and 15-minute moving averages like Unix load averages.
+// Meter is safe for concurrent use.
+type Meter struct {
+	mu    sync.Mutex
+	clock Clock
+	start time.Time
+	count int64
+	m1    *EWMA
+	m5    *EWMA
+	m15   *EWMA
+}
+
+// MeterSnapshot is the state of Meter at a moment.
+type MeterSnapshot struct {
+	Count  int64   `json:"count"`
+	Mean   float64 `json:"mean"`
+	Rate1  float64 `json:"rate1"`
+	Rate5  float64 `json:"rate5"`
+	Rate15 float64 `json:"rate15"`
+}
+
+func (s MeterSnapshot) String() string {
+	return fmt.Sprintf("count=%d mean=%.2f/s 1m=%.2f/s 5m=%.2f/s 15m=%.2f/s",
+		s.Count, s.Mean, s.Rate1, s.Rate5, s.Rate15)
+}
+
+// NewMeter returns Meter started now, the clock is SystemClock if nil.
+func NewMeter(clock Clock) *Meter {
+	if clock == nil {
+		clock = SystemClock
+	}
+	return &Meter{
+		clock: clock,
+		start: clock.Now(),
+		m1:    NewEWMA(time.Minute, clock),
+		m5:    NewEWMA(5*time.Minute, clock),
+		m15:   NewEWMA(15*time.Minute, clock),
+	}
+}
+
+// Mark counts n events.
+func (m *Meter) Mark(n int64) {
+	m.mu.Lock()
+	m.count += n
+	m.mu.Unlock()
+	m.m1.Update(n)
+	m.m5.Update(n)
+	m.m15.Update(n)
+}
+
+// Count returns the number of events.
+func (m *Meter) Count() int64 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.count
+}
+
+// RateMean returns the mean number of events per second since the start.
+func (m *Meter) RateMean() float64 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	elapsed := m.clock.Now().Sub(m.start).Seconds()
+	if elapsed <= 0 {
+		return 0
+	}
+	return float64(m.count) / elapsed
+}
+
+// Rate1 returns the one-minute moving average of events per second.
+func (m *Meter) Rate1() float64 {
+	return m.m1.Rate()
+}
+
+// Rate5 returns the five-minute moving average of events per second.
+func (m *Meter) Rate5() float64 {
+	return m.m5.Rate()
+}
+
+// Rate15 returns the fifteen-minute moving average of events per second.
+func (m *Meter) Rate15() float64 {
+	return m.m15.Rate()
+}
+
+// Snapshot returns the count and the rates of the meter.
+func (m *Meter) Snapshot() MeterSnapshot {
+	return MeterSnapshot{
+		Count:  m.Count(),
+		Mean:   m.RateMean(),
+		Rate1:  m.Rate1(),
+		Rate5:  m.Rate5(),
+		Rate15: m.Rate15(),
+	}
+}
@@ -0,0 +1,122 @@
+package mathutils
+
+import (
+	"math"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	c.now = c.now.Add(d)
+	c.mu.Unlock()
+}
+
+func TestEWMA(t *testing.T) {
+	cases := []struct {
+		window time.Duration
+		after  time.Duration
+	}{
+		{
+			window: time.Minute,
+			after:  time.Minute,
+		},
+		{
+			window: 5 * time.Minute,
+			after:  time.Minute,
+		},
+		{
+			window: 15 * time.Minute,
+			after:  5 * time.Minute,
+		},
+		{
+			window: time.Minute,
+			after:  0,
+		},
+	}
+	for _, c := range cases {
+		clock := &fakeClock{now: time.Unix(1000, 0)}
+		e := NewEWMA(c.window, clock)
+		e.Update(3)
+		assert.Equal(t, 0.0, e.Rate(), "events of the current tick are not counted")
+		clock.Advance(TickInterval)
+		assert.InDelta(t, 0.6, e.Rate(), 1e-12)
+		// without events the rate decays by e each window
+		clock.Advance(c.after)
+		expected := 0.6 * math.Exp(-c.after.Seconds()/c.window.Seconds())
+		assert.InDelta(t, expected, e.Rate(), 1e-12, c.window, c.after)
+	}
+	assert.Panics(t, func() { NewEWMA(0, nil) })
+}
+
+func TestEWMASteady(t *testing.T) {
+	clock := &fakeClock{now: time.Unix(1000, 0)}
+	e := NewEWMA(time.Minute, clock)
+	for i := 0; i < 120; i++ {
+		e.Update(50)
+		clock.Advance(time.Second)
+	}
+	assert.InDelta(t, 50, e.Rate(), 1e-9)
+	// the time shorter than the tick is carried over to the next tick
+	clock.Advance(TickInterval - time.Nanosecond)
+	assert.InDelta(t, 50, e.Rate(), 1e-9)
+	clock.Advance(time.Nanosecond)
+	assert.InDelta(t, 50*(1-e.alpha), e.Rate(), 1e-9)
+}
+
+func TestMeter(t *testing.T) {
+	clock := &fakeClock{now: time.Unix(1000, 0)}
+	m := NewMeter(clock)
+	assert.Equal(t, MeterSnapshot{}, m.Snapshot())
+	for i := 0; i < 60; i++ {
+		m.Mark(10)
+		clock.Advance(time.Second)
+	}
+	s := m.Snapshot()
+	assert.Equal(t, int64(600), s.Count)
+	assert.InDelta(t, 10, s.Mean, 1e-9)
+	assert.InDelta(t, 10, s.Rate1, 1e-9)
+	assert.InDelta(t, 10, s.Rate5, 1e-9)
+	assert.InDelta(t, 10, s.Rate15, 1e-9)
+
+	clock.Advance(5 * time.Minute)
+	assert.InDelta(t, 10*math.Exp(-5), m.Rate1(), 1e-9)
+	assert.InDelta(t, 10*math.Exp(-1), m.Rate5(), 1e-9)
+	assert.InDelta(t, 10*math.Exp(-1.0/3), m.Rate15(), 1e-9)
+	assert.InDelta(t, 600.0/360, m.RateMean(), 1e-9)
+	assert.Equal(t, "count=600 mean=1.67/s 1m=0.07/s 5m=3.68/s 15m=7.17/s", m.Snapshot().String())
+}
+
+func TestMeterConcurrent(t *testing.T) {
+	clock := &fakeClock{now: time.Unix(1000, 0)}
+	m := NewMeter(clock)
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 1000; i++ {
+				m.Mark(1)
+				m.Rate1()
+			}
+		}()
+	}
+	wg.Wait()
+	clock.Advance(TickInterval)
+	assert.Equal(t, int64(8000), m.Count())
+	assert.InDelta(t, 1600, m.Rate1(), 1e-9)
+}
@@ -0,0 +1,339 @@
+package mathutils
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"sort"
+	"sync"
+)
+
+// DefaultAccuracy is the relative accuracy of the quantiles of Stats created without NewStats.
+const DefaultAccuracy = 0.01
+
+// ErrAccuracyMismatch is returned by Merge when the accuracies of the quantiles differ.
+var ErrAccuracyMismatch = errors.New("mathutils: stats accuracies differ")
+
+// Stats accumulates float samples online: count, mean and variance by the Welford's algorithm,
+// min, max and approximate quantiles by the logarithmic sketch (like DDSketch).
+// The quantiles are within the relative accuracy of the true values,
+// the memory grows with the logarithm of the range of samples only.
+// The zero value is ready to use with DefaultAccuracy, Stats is safe for concurrent use.
+type Stats struct {
+	mu       sync.Mutex
+	accuracy float64
+	gamma    float64
+	count    uint64
+	mean     float64
+	m2       float64
+	min      float64
+	max      float64
+	zeros    uint64
+	positive map[int]uint64
+	negative map[int]uint64
+}
+
+// Summary is the snapshot of Stats.
+type Summary struct {
+	Count  uint64  `json:"count"`
+	Mean   float64 `json:"mean"`
+	StdDev float64 `json:"stddev"`
+	Min    float64 `json:"min"`
+	Max    float64 `json:"max"`
+	P50    float64 `json:"p50"`
+	P95    float64 `json:"p95"`
+	P99    float64 `json:"p99"`
+}
+
+// NewStats returns Stats with the relative accuracy of quantiles in range (0, 1), e.g. 0.01 for 1%.
+func NewStats(accuracy float64) *Stats {
+	if accuracy <= 0 || accuracy >= 1 {
+		panic("mathutils: accuracy must be in range (0, 1)")
+	}
+	return &Stats{accuracy: accuracy}
+}
+
+// init sets the defaults of the zero value, it is called with the lock held.
+func (s *Stats) init() {
+	if s.accuracy == 0 {
+		s.accuracy = DefaultAccuracy
+	}
+	if s.gamma == 0 {
+		s.gamma = (1 + s.accuracy) / (1 - s.accuracy)
+	}
+	if s.positive == nil {
+		s.positive = make(map[int]uint64)
+		s.negative = make(map[int]uint64)
+	}
+}
+
+// Add adds the sample, NaN and infinities are ignored.
+func (s *Stats) Add(x float64) {
+	if math.IsNaN(x) || math.IsInf(x, 0) {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.init()
+	s.count++
+	delta := x - s.mean
+	s.mean += delta / float64(s.count)
+	s.m2 += delta * (x - s.mean)
+	if s.count == 1 || x < s.min {
+		s.min = x
+	}
+	if s.count == 1 || x > s.max {
+		s.max = x
+	}
+	switch {
+	case x > 0:
+		s.positive[s.index(x)]++
+	case x < 0:
+		s.negative[s.index(-x)]++
+	default:
+		s.zeros++
+	}
+}
+
+// index returns the bucket of the positive value, the bucket i holds values in (gamma^(i-1), gamma^i].
+func (s *Stats) index(x float64) int {
+	return int(math.Ceil(math.Log(x) / math.Log(s.gamma)))
+}
+
+// value returns the estimate of the values of the bucket with the relative error at most the accuracy.
+func (s *Stats) value(i int) float64 {
+	return 2 * math.Pow(s.gamma, float64(i)) / (s.gamma + 1)
+}
+
+// Count returns the number of samples.
+func (s *Stats) Count() uint64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.count
+}
+
+// Mean returns the mean of samples or 0 if there are no samples.
+func (s *Stats) Mean() float64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.mean
+}
+
+// Variance returns the sample variance (divided by count-1) or 0 if there are less than two samples.
+func (s *Stats) Variance() float64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.variance()
+}
+
+func (s *Stats) variance() float64 {
+	if s.count < 2 {
+		return 0
+	}
+	return s.m2 / float64(s.count-1)
+}
+
+// StdDev returns the sample standard deviation.
+func (s *Stats) StdDev() float64 {
+	return math.Sqrt(s.Variance())
+}
+
+// Min returns the minimum sample or 0 if there are no samples.
+func (s *Stats) Min() float64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.min
+}
+
+// Max returns the maximum sample or 0 if there are no samples.
+func (s *Stats) Max() float64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.max
+}
+
+// Quantile returns the approximate q-quantile (q in range [0, 1]) of samples
+// or 0 if there are no samples, e.g. Quantile(0.99) is the 99th percentile.
+func (s *Stats) Quantile(q float64) float64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.quantile(q)
+}
+
+func (s *Stats) quantile(q float64) float64 {
+	if s.count == 0 {
+		return 0
+	}
+	if q <= 0 {
+		return s.min
+	}
+	if q >= 1 {
+		return s.max
+	}
+	rank := uint64(q * float64(s.count-1))
+	var x float64
+	// the negative buckets are walked from the largest magnitude
+	if n := sumCounts(s.negative); rank < n {
+		x = -s.value(walkBuckets(s.negative, n-1-rank))
+	} else if rank -= n; rank < s.zeros {
+		x = 0
+	} else {
+		x = s.value(walkBuckets(s.positive, rank-s.zeros))
+	}
+	return math.Max(s.min, math.Min(s.max, x))
+}
+
+// sumCounts returns the number of samples in the buckets.
+func sumCounts(buckets map[int]uint64) uint64 {
+	var n uint64
+	for _, c := range buckets {
+		n += c
+	}
+	return n
+}
+
+// walkBuckets returns the bucket holding the sample of the rank in ascending order.
+func walkBuckets(buckets map[int]uint64, rank uint64) int {
+	keys := make([]int, 0, len(buckets))
+	for k := range buckets {
+		keys = append(keys, k)
+	}
+	sort.Ints(keys)
+	for _, k := range keys {
+		if rank < buckets[k] {
+			return k
+		}
+		rank -= buckets[k]
+	}
+	return keys[len(keys)-1]
+}
+
+// Summary returns the snapshot of count, mean, standard deviation, min, max
+// and 50th, 95th and 99th percentiles.
+func (s *Stats) Summary() Summary {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return Summary{
+		Count:  s.count,
+		Mean:   s.mean,
+		StdDev: math.Sqrt(s.variance()),
+		Min:    s.min,
+		Max:    s.max,
+		P50:    s.quantile(0.5),
+		P95:    s.quantile(0.95),
+		P99:    s.quantile(0.99),
+	}
+}
+
+// Merge adds the samples of other to s, e.g. the stats collected by several goroutines.
+// The accuracies of both stats must be the same.
+func (s *Stats) Merge(other *Stats) error {
+	// other is copied first to not hold both locks
+	o := other.state()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.init()
+	if o.Accuracy != s.accuracy {
+		return ErrAccuracyMismatch
+	}
+	s.merge(o)
+	return nil
+}
+
+func (s *Stats) merge(o statsState) {
+	if o.Count == 0 {
+		return
+	}
+	if s.count == 0 || o.Min < s.min {
+		s.min = o.Min
+	}
+	if s.count == 0 || o.Max > s.max {
+		s.max = o.Max
+	}
+	// the Chan's formula of the combined mean and variance
+	count := s.count + o.Count
+	delta := o.Mean - s.mean
+	s.m2 += o.M2 + delta*delta*float64(s.count)*float64(o.Count)/float64(count)
+	s.mean += delta * float64(o.Count) / float64(count)
+	s.count = count
+	s.zeros += o.Zeros
+	for k, c := range o.Positive {
+		s.positive[k] += c
+	}
+	for k, c := range o.Negative {
+		s.negative[k] += c
+	}
+}
+
+// Reset removes all samples, the accuracy is kept.
+func (s *Stats) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.count, s.mean, s.m2, s.min, s.max, s.zeros = 0, 0, 0, 0, 0, 0
+	s.positive, s.negative = nil, nil
+}
+
+// statsState is the serialized form of Stats.
+type statsState struct {
+	Accuracy float64        `json:"accuracy"`
+	Count    uint64         `json:"count"`
+	Mean     float64        `json:"mean"`
+	M2       float64        `json:"m2"`
+	Min      float64        `json:"min"`
+	Max      float64        `json:"max"`
+	Zeros    uint64         `json:"zeros,omitempty"`
+	Positive map[int]uint64 `json:"positive,omitempty"`
+	Negative map[int]uint64 `json:"negative,omitempty"`
+}
+
+func (s *Stats) state() statsState {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.init()
+	st := statsState{
+		Accuracy: s.accuracy,
+		Count:    s.count,
+		Mean:     s.mean,
+		M2:       s.m2,
+		Min:      s.min,
+		Max:      s.max,
+		Zeros:    s.zeros,
+		Positive: make(map[int]uint64, len(s.positive)),
+		Negative: make(map[int]uint64, len(s.negative)),
+	}
+	for k, c := range s.positive {
+		st.Positive[k] = c
+	}
+	for k, c := range s.negative {
+		st.Negative[k] = c
+	}
+	return st
+}
+
+// MarshalJSON encodes the stats with the buckets of quantiles,
+// so the decoded stats can be merged and updated further.
+func (s *Stats) MarshalJSON() ([]byte, error) {
+	return json.Marshal(s.state())
+}
+
+// UnmarshalJSON replaces the stats with the decoded ones.
+func (s *Stats) UnmarshalJSON(data []byte) error {
+	var st statsState
+	if err := json.Unmarshal(data, &st); err != nil {
+		return err
+	}
+	if st.Accuracy <= 0 || st.Accuracy >= 1 {
+		return errors.New("mathutils: invalid stats accuracy")
+	}
+	if sumCounts(st.Positive)+sumCounts(st.Negative)+st.Zeros != st.Count {
+		return errors.New("mathutils: invalid stats buckets")
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.count, s.mean, s.m2, s.min, s.max, s.zeros = 0, 0, 0, 0, 0, 0
+	s.accuracy, s.gamma = st.Accuracy, 0
+	s.positive, s.negative = nil, nil
+	s.init()
+	s.merge(st)
+	return nil
+}
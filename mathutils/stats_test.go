@@ -0,0 +1,155 @@
+package mathutils
+
+import (
+	"encoding/json"
+	"math"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStats(t *testing.T) {
+	var s Stats
+	assert.Equal(t, Summary{}, s.Summary())
+	for _, x := range []float64{2, 4, 4, 4, 5, 5, 7, 9, math.NaN(), math.Inf(1)} {
+		s.Add(x)
+	}
+	assert.Equal(t, uint64(8), s.Count())
+	assert.InDelta(t, 5, s.Mean(), 1e-12)
+	assert.InDelta(t, 32.0/7, s.Variance(), 1e-12)
+	assert.InDelta(t, math.Sqrt(32.0/7), s.StdDev(), 1e-12)
+	assert.Equal(t, 2.0, s.Min())
+	assert.Equal(t, 9.0, s.Max())
+	assert.Equal(t, 2.0, s.Quantile(0))
+	assert.Equal(t, 9.0, s.Quantile(1))
+	assert.InDelta(t, 4, s.Quantile(0.5), 4*DefaultAccuracy)
+
+	s.Reset()
+	assert.Equal(t, uint64(0), s.Count())
+	assert.Equal(t, 0.0, s.Quantile(0.5))
+}
+
+func TestStatsQuantiles(t *testing.T) {
+	cases := []struct {
+		accuracy float64
+		q        float64
+		expected float64
+	}{
+		{
+			accuracy: 0.01,
+			q:        0.5,
+			expected: 5000,
+		},
+		{
+			accuracy: 0.01,
+			q:        0.95,
+			expected: 9500,
+		},
+		{
+			accuracy: 0.01,
+			q:        0.99,
+			expected: 9900,
+		},
+		{
+			accuracy: 0.001,
+			q:        0.99,
+			expected: 9900,
+		},
+		{
+			accuracy: 0.05,
+			q:        0.01,
+			expected: 100,
+		},
+	}
+	for _, c := range cases {
+		s := NewStats(c.accuracy)
+		// the samples are added in the descending order to check the order does not matter
+		for i := 10000; i >= 1; i-- {
+			s.Add(float64(i))
+		}
+		assert.InDelta(t, c.expected, s.Quantile(c.q), c.expected*c.accuracy+1, c.q)
+	}
+	assert.Panics(t, func() { NewStats(0) })
+	assert.Panics(t, func() { NewStats(1) })
+}
+
+func TestStatsNegative(t *testing.T) {
+	var s Stats
+	for i := -1000; i <= 1000; i++ {
+		s.Add(float64(i))
+	}
+	assert.InDelta(t, 0, s.Mean(), 1e-9)
+	assert.Equal(t, 0.0, s.Quantile(0.5))
+	assert.InDelta(t, -900, s.Quantile(0.05), 900*DefaultAccuracy+1)
+	assert.InDelta(t, 900, s.Quantile(0.95), 900*DefaultAccuracy+1)
+	assert.Equal(t, -1000.0, s.Min())
+	assert.Equal(t, 1000.0, s.Max())
+}
+
+func TestStatsMerge(t *testing.T) {
+	var all, even, odd Stats
+	for i := 1; i <= 1001; i++ {
+		x := float64(i * i % 997)
+		all.Add(x)
+		if i%2 == 0 {
+			even.Add(x)
+		} else {
+			odd.Add(x)
+		}
+	}
+	var merged Stats
+	assert.NoError(t, merged.Merge(&even))
+	assert.NoError(t, merged.Merge(&odd))
+	expected, actual := all.Summary(), merged.Summary()
+	assert.Equal(t, expected.Count, actual.Count)
+	assert.InDelta(t, expected.Mean, actual.Mean, 1e-9)
+	assert.InDelta(t, expected.StdDev, actual.StdDev, 1e-9)
+	assert.Equal(t, expected.Min, actual.Min)
+	assert.Equal(t, expected.Max, actual.Max)
+	assert.Equal(t, expected.P50, actual.P50)
+	assert.Equal(t, expected.P99, actual.P99)
+
+	assert.Equal(t, ErrAccuracyMismatch, NewStats(0.02).Merge(&all))
+}
+
+func TestStatsConcurrent(t *testing.T) {
+	var (
+		s  Stats
+		wg sync.WaitGroup
+	)
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			var local Stats
+			for i := 1; i <= 1000; i++ {
+				s.Add(float64(i))
+				local.Add(float64(i))
+			}
+			assert.NoError(t, s.Merge(&local))
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, uint64(16000), s.Count())
+	assert.InDelta(t, 500.5, s.Mean(), 1e-9)
+}
+
+func TestStatsJSON(t *testing.T) {
+	s := NewStats(0.02)
+	for i := -50; i <= 200; i++ {
+		s.Add(float64(i) / 4)
+	}
+	data, err := json.Marshal(s)
+	assert.NoError(t, err)
+
+	var decoded Stats
+	assert.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, s.Summary(), decoded.Summary())
+	// the decoded stats keep the accuracy and can be merged
+	assert.NoError(t, decoded.Merge(s))
+	assert.Equal(t, 2*s.Count(), decoded.Count())
+
+	assert.Error(t, json.Unmarshal([]byte(`{"accuracy":0,"count":0}`), &decoded))
+	assert.Error(t, json.Unmarshal([]byte(`{"accuracy":0.01,"count":2,"zeros":1}`), &decoded))
+}
@@ -1,6 +1,7 @@
 package net
 
 import (
+	"context"
 	"crypto/rand"
 	"errors"
 	"fmt"
@@ -28,14 +29,23 @@ type NetHandler struct {
 	peerId   string
 	peerName string
 	view     view.View
+	backoff  backoff.Config
+	ctx      context.Context
 }
 
 func New(network, address, peerName string) *NetHandler {
+	return NewWithConfig(network, address, peerName, backoff.DefaultConfig)
+}
+
+// NewWithConfig returns a NetHandler which reconnects to the server
+// with delays defined by passed backoff configuration.
+func NewWithConfig(network, address, peerName string, config backoff.Config) *NetHandler {
 	return &NetHandler{
 		network:  network,
 		address:  address,
 		peerId:   getPeerId(),
 		peerName: peerName,
+		backoff:  config,
 	}
 }
 
@@ -43,7 +53,22 @@ func (h *NetHandler) Init(v view.View, room string) error {
 	h.view = v
 	vch := h.view.Show()
 
+	// the context is done when the view is closed to stop connecting and reconnecting
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	h.ctx = ctx
+	closed := make(chan struct{})
+	go func() {
+		<-vch
+		cancel()
+		close(closed)
+	}()
+
 	if err := h.connect(); err != nil {
+		if ctx.Err() != nil {
+			// the view is already closed
+			return err
+		}
 		h.view.ViewMessage(view.InfoMessage, "", "Unable to connect to the server. Please, try later...")
 		<-time.After(3 * time.Second)
 		h.view.Quit()
@@ -71,7 +96,7 @@ func (h *NetHandler) Init(v view.View, room string) error {
 		}
 	}
 
-	<-vch
+	<-closed
 
 	return nil
 }
@@ -128,21 +153,25 @@ func (h *NetHandler) connect() error {
 		h.view.ViewMessage(view.TailMessage, "", "...")
 	}
 	// try connect
-	var err error
-	eb := backoff.NewExpBackoff()
-	for {
-		if h.conn, err = net.Dial(h.network, h.address); err != nil {
-			if eb.Attempts() == uint64(attemptsToConnect) {
-				break
-			}
-			<-eb.Delay()
-		} else {
-			tryConnectMsg = ""
-			h.view.ViewMessage(view.InfoMessage, "", "You are connected to the server!")
-			return nil
-		}
+	config := h.backoff
+	config.OnRetry = func(attempt uint64, delay time.Duration, err error) {
+		h.view.ViewMessage(view.InfoMessage, "",
+			fmt.Sprintf("Attempt %d to connect failed: %s. Next attempt in %v.", attempt, err, delay.Round(time.Millisecond)))
+	}
+	err := backoff.Retry(h.ctx, func() error {
+		var (
+			d   net.Dialer
+			err error
+		)
+		h.conn, err = d.DialContext(h.ctx, h.network, h.address)
+		return err
+	}, backoff.WithBackoff(backoff.New(config)), backoff.WithMaxAttempts(uint64(attemptsToConnect)))
+	if err != nil {
+		return err
 	}
-	return err
+	tryConnectMsg = ""
+	h.view.ViewMessage(view.InfoMessage, "", "You are connected to the server!")
+	return nil
 }
 
 func (h *NetHandler) disconnect() {
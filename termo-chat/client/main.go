@@ -9,6 +9,7 @@ import (
 	"os/user"
 	"strings"
 
+	"github.com/austinov/go-recipes/backoff"
 	"github.com/austinov/go-recipes/termo-chat/client/handler/net"
 	_ "github.com/austinov/go-recipes/termo-chat/client/view/simple"
 	"github.com/austinov/go-recipes/termo-chat/client/view/term"
@@ -20,8 +21,15 @@ func main() {
 		laddr string
 		room  string
 	)
+	bc := backoff.DefaultConfig
+	// spread reconnection attempts of many clients
+	bc.JitterMode = backoff.JitterEqual
 	flag.StringVar(&room, "room", "",
 		"Room identity")
+	flag.Var(&bc.Policy, "backoff",
+		"The policy of delays between attempts to connect: exponential, constant, linear, fibonacci or decorrelated.")
+	flag.Var(&bc.JitterMode, "jitter",
+		"The jitter of delays between attempts to connect: none, normal, full or equal.")
 
 	flag.StringVar(&laddr, "addr", ":8822",
 		"The syntax of addr is \"host:port\", like \"127.0.0.1:8822\". "+
@@ -31,7 +39,7 @@ func main() {
 
 	un := getUserName()
 
-	hdl := net.New(netw, laddr, un)
+	hdl := net.NewWithConfig(netw, laddr, un, bc)
 	view := term.New(un, hdl)
 	//view := simple.New(hdl)
 
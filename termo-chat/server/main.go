@@ -14,6 +14,8 @@ import (
 	"syscall"
 	"time"
 
+	"github.com/austinov/go-recipes/flagutils"
+	"github.com/austinov/go-recipes/mathutils"
 	"github.com/austinov/go-recipes/termo-chat/common/proto"
 
 	"github.com/ugorji/go/codec"
@@ -53,13 +55,22 @@ var (
 
 	muc   sync.Mutex
 	conns = make(map[net.Conn]string) // value is room id
+
+	messages = mathutils.NewMeter(nil) // throughput of chat messages
 )
 
 func main() {
-	flag.StringVar(&laddr, "addr", ":8822",
+	addr := flagutils.String(flag.CommandLine, "addr", ":8822",
 		"The syntax of addr is \"host:port\", like \"127.0.0.1:8822\". "+
-			"If host is omitted, as in \":8822\", Listen listens on all available interfaces.")
+			"If host is omitted, as in \":8822\", Listen listens on all available interfaces. "+
+			"It can be set by CHAT_ADDR environment variable.")
+	report := flagutils.Duration(flag.CommandLine, "report", 0,
+		"interval of logging the throughput of messages, 0 disables it (or CHAT_REPORT environment variable)")
 	flag.Parse()
+	if err := flagutils.Resolve(flag.CommandLine, "chat", ""); err != nil {
+		log.Fatal(err)
+	}
+	laddr = addr.Value
 
 	var err error
 	if listener, err = net.Listen(netw, laddr); err != nil {
@@ -80,6 +91,10 @@ func main() {
 		signal.Stop(interrupt)
 	}()
 
+	if report.Value > 0 {
+		go reportThroughput(report.Value, done)
+	}
+
 	// start message senders
 	for i := 0; i < numSenders; i++ {
 		go send(done)
@@ -322,6 +337,7 @@ func sendMsg(conn net.Conn, p proto.Packet) {
 			Sender:  room.peers[p.Data.PeerId].name,
 		})
 	sendOthers(pd, room, p.Data.PeerId)
+	messages.Mark(1)
 }
 
 func leaveRoom(conn net.Conn, p proto.Packet) {
@@ -366,6 +382,20 @@ func send(done <-chan struct{}) {
 	}
 }
 
+// reportThroughput logs the rates of messages every interval until done is closed.
+func reportThroughput(interval time.Duration, done <-chan struct{}) {
+	t := time.NewTicker(interval)
+	defer t.Stop()
+	for {
+		select {
+		case <-done:
+			return
+		case <-t.C:
+			log.Printf("messages: %s\n", messages.Snapshot())
+		}
+	}
+}
+
 func closeListener() {
 	if !closed {
 		listener.Close()
@@ -2,6 +2,7 @@ package bot
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -14,6 +15,10 @@ import (
 	"sync"
 	"sync/atomic"
 	"time"
+
+	"github.com/austinov/go-recipes/backoff"
+	"github.com/austinov/go-recipes/backoff/breaker"
+	"github.com/austinov/go-recipes/mathutils"
 )
 
 const (
@@ -22,6 +27,9 @@ const (
 	pollDelay       = 1 * time.Second
 	numPollers      = 2
 	numSenders      = 3
+	sendAttempts    = 3
+	retryRatio      = 0.1 // retries of replies per successful reply
+	retryTokens     = 10
 	reverseCommand  = "/reverse"
 	searchCommand   = "/search"
 	roulleteCommand = "/roullete"
@@ -33,17 +41,47 @@ type Bot struct {
 	updates chan []Update
 	replies chan Reply
 	done    chan struct{}
+	ctx     context.Context
+	cancel  context.CancelFunc
+	backoff backoff.Config
+	breaker *breaker.Breaker
+	// budget is shared by the senders to limit retries of replies
+	budget *backoff.Budget
+	// sent counts the replies delivered into chats
+	sent *mathutils.Meter
 }
 
 func New(token string) *Bot {
+	return NewWithConfig(token, backoff.DefaultConfig)
+}
+
+// NewWithConfig returns a bot which delays polling after failures
+// according to passed backoff configuration,
+// the delays dictated by Telegram are used as is.
+func NewWithConfig(token string, config backoff.Config) *Bot {
 	if token == "" {
 		log.Fatal("Token is empty")
 	}
+	// retry_after of Telegram must be waited in full, clamping it to MaxDelay gives another 429
+	config.HintMode = backoff.HintOverride
+	bc := breaker.DefaultConfig
+	bc.OnStateChange = func(from, to breaker.State) {
+		log.Printf("Telegram API circuit breaker: %s -> %s\n", from, to)
+	}
 	return &Bot{
-		token: token,
+		token:   token,
+		backoff: config,
+		breaker: breaker.New(bc),
+		budget:  backoff.NewBudget(retryRatio, retryTokens),
+		sent:    mathutils.NewMeter(nil),
 	}
 }
 
+// Throughput returns the count and the rates of replies sent by the bot.
+func (b *Bot) Throughput() mathutils.MeterSnapshot {
+	return b.sent.Snapshot()
+}
+
 // Start runs bot to receive incoming updates using long polling,
 // to handle messages and to send replies into chat.
 // It blocks until the "Stop" method is called.
@@ -54,6 +92,7 @@ func (b *Bot) Start() {
 	b.updates = make(chan []Update, numPollers)
 	b.replies = make(chan Reply, numSenders)
 	b.done = make(chan struct{})
+	b.ctx, b.cancel = context.WithCancel(context.Background())
 
 	var wg sync.WaitGroup
 
@@ -72,11 +111,13 @@ func (b *Bot) Start() {
 
 // Stop initiates a stop of the bot.
 func (b *Bot) Stop() {
+	b.cancel()
 	close(b.done)
 }
 
 func (b *Bot) pollUpdates(wg *sync.WaitGroup) {
 	defer wg.Done()
+	eb := backoff.New(b.backoff)
 	for {
 		select {
 		case _, ok := <-b.done:
@@ -84,7 +125,15 @@ func (b *Bot) pollUpdates(wg *sync.WaitGroup) {
 				return
 			}
 		default:
-			b.poll()
+			if err := b.poll(); err != nil {
+				log.Println(err)
+				backoff.HintError(eb, err)
+				if err := backoff.Wait(b.ctx, eb); err != nil {
+					return
+				}
+			} else {
+				eb.Reset()
+			}
 		}
 	}
 }
@@ -125,7 +174,7 @@ func (b *Bot) processReplies(wg *sync.WaitGroup) {
 	}
 }
 
-func (b *Bot) poll() {
+func (b *Bot) poll() error {
 	log.Printf("Try to get updates...\n")
 
 	ur := UpdateParams{
@@ -134,8 +183,7 @@ func (b *Bot) poll() {
 	}
 	var updates Updates
 	if err := b.callAPI("getUpdates", ur, &updates); err != nil {
-		log.Println(err)
-		return
+		return err
 	}
 
 	l := len(updates.Result)
@@ -144,6 +192,7 @@ func (b *Bot) poll() {
 		atomic.StoreUint64(&b.offset, updates.Result[l-1].UpdateId+1)
 		b.updates <- updates.Result
 	}
+	return nil
 }
 
 func (b *Bot) processMessage(msg Message) {
@@ -172,13 +221,35 @@ func (b *Bot) processMessage(msg Message) {
 func (b *Bot) sendReply(reply Reply) {
 	log.Printf("Send reply: %#v\n", reply)
 
-	var result Result
-	if err := b.callAPI("sendMessage", reply, &result); err != nil {
+	config := b.backoff
+	config.Budget = b.budget
+	err := backoff.Retry(b.ctx, func() error {
+		var result Result
+		err := b.callAPI("sendMessage", reply, &result)
+		// the open breaker already counts the outage, retries would only spend the budget
+		if errors.Is(err, breaker.ErrOpen) || errors.Is(err, breaker.ErrTooManyRequests) {
+			return backoff.Permanent(err)
+		}
+		return err
+	}, backoff.WithBackoff(backoff.New(config)), backoff.WithMaxAttempts(sendAttempts))
+	if err != nil {
 		log.Println(err)
+		if stats := b.budget.Stats(); stats.Denied > 0 {
+			log.Printf("Retries of replies: %d, denied: %d\n", stats.Retries, stats.Denied)
+		}
+		return
 	}
+	b.sent.Mark(1)
 }
 
+// callAPI calls the method of Telegram API unless the circuit breaker is open.
 func (b *Bot) callAPI(method string, data interface{}, result interface{}) error {
+	return b.breaker.Execute(func() error {
+		return b.call(method, data, result)
+	})
+}
+
+func (b *Bot) call(method string, data interface{}, result interface{}) error {
 	commandUrl := fmt.Sprintf(apiURL, b.token, method)
 
 	jsonData, err := json.Marshal(data)
@@ -196,6 +267,14 @@ func (b *Bot) callAPI(method string, data interface{}, result interface{}) error
 	}
 	defer resp.Body.Close()
 
+	if resp.StatusCode == http.StatusTooManyRequests {
+		err := errors.New(fmt.Sprintf("Request %s failed with code %d", method, resp.StatusCode))
+		if delay, ok := retryAfter(resp); ok {
+			// the retries of the reply wait as long as Telegram asks
+			return backoff.RetryAfter(err, delay)
+		}
+		return err
+	}
 	if resp.StatusCode != 200 {
 		return errors.New(fmt.Sprintf("Request %s failed with code %d", method, resp.StatusCode))
 	}
@@ -216,6 +295,19 @@ func (b *Bot) callAPI(method string, data interface{}, result interface{}) error
 	return nil
 }
 
+// retryAfter returns the delay of the flood control from Retry-After header
+// or from "parameters.retry_after" of the response body.
+func retryAfter(resp *http.Response) (time.Duration, bool) {
+	if delay, ok := backoff.ResponseRetryAfter(resp, time.Now()); ok {
+		return delay, true
+	}
+	var r Response
+	if err := json.NewDecoder(resp.Body).Decode(&r); err != nil || r.Parameters.RetryAfter <= 0 {
+		return 0, false
+	}
+	return time.Duration(r.Parameters.RetryAfter) * time.Second, true
+}
+
 // helpHandler returns a reply containing help text.
 func (b *Bot) helpHandler(msg Message) Reply {
 	cmd := "Please, use the follow commands:\n" +
@@ -2,9 +2,16 @@ package bot
 
 // These are structures represent the response of the Telegram API
 type Response struct {
-	Ok          bool   `json:"ok"`
-	ErrorCode   int    `json:"error_code"`
-	Description string `json:"description"`
+	Ok          bool               `json:"ok"`
+	ErrorCode   int                `json:"error_code"`
+	Description string             `json:"description"`
+	Parameters  ResponseParameters `json:"parameters"`
+}
+
+// ResponseParameters describes why a request was unsuccessful.
+type ResponseParameters struct {
+	// RetryAfter is the number of seconds to wait when flood control is exceeded
+	RetryAfter int `json:"retry_after"`
 }
 
 type Message struct {
@@ -3,26 +3,64 @@ package main
 import (
 	"flag"
 	"log"
+	"os"
 	"time"
 
+	"github.com/austinov/go-recipes/backoff"
+	"github.com/austinov/go-recipes/flagutils"
 	"github.com/austinov/go-recipes/tg-bot/bot"
 )
 
 func main() {
-	var token string
-	flag.StringVar(&token, "t", "", "telegram token")
+	bc := backoff.DefaultConfig
+	token := flagutils.String(flag.CommandLine, "t", "", "telegram token (or TGBOT_T environment variable)")
+	flag.Var(&bc.Policy, "backoff", "policy of delays after failed polling: exponential, constant, linear, fibonacci or decorrelated")
+	showConfig := flag.Bool("show-config", false, "print the effective configuration")
+	report := flagutils.Duration(flag.CommandLine, "report", 0,
+		"interval of logging the throughput of replies, 0 disables it (or TGBOT_REPORT environment variable)")
 	flag.Parse()
+	if err := flagutils.Resolve(flag.CommandLine, "tgbot", ""); err != nil {
+		log.Fatal(err)
+	}
+	if *showConfig {
+		flagutils.WriteTable(os.Stderr, flagutils.Effective(flag.CommandLine, "t"))
+	}
 
-	b := bot.New(token)
+	b := bot.NewWithConfig(token.Value, bc)
 	go func() {
 		<-time.After(1 * time.Minute)
 		log.Println("Stop telegram bot.")
 		b.Stop()
 	}()
 	log.Println("Start telegram bot.")
+	stop := reportThroughput(b, report.Value)
 	b.Start()
+	stop()
 
-	b = bot.New(token)
+	b = bot.NewWithConfig(token.Value, bc)
 	log.Println("Start telegram bot again.")
+	stop = reportThroughput(b, report.Value)
 	b.Start()
+	stop()
+}
+
+// reportThroughput logs the rates of replies of the bot every interval until stop is called,
+// nothing is logged if interval is not positive.
+func reportThroughput(b *bot.Bot, interval time.Duration) (stop func()) {
+	done := make(chan struct{})
+	if interval > 0 {
+		go func() {
+			t := time.NewTicker(interval)
+			defer t.Stop()
+			for {
+				select {
+				case <-done:
+					return
+				case <-t.C:
+					log.Printf("Replies: %s\n", b.Throughput())
+				}
+			}
+		}()
+	}
+	return func() { close(done) }
 }